@@ -7,10 +7,19 @@
 package config
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
 	uconfig "go.uber.org/config"
 )
 
+const (
+	defaultMaxBlockAge = time.Minute
+
+	OnlineMode  = "online"
+	OfflineMode = "offline"
+)
+
 type (
 	NetworkIdentifier struct {
 		Blockchain   string `yaml:"blockchain"`
@@ -21,17 +30,61 @@ type (
 		Symbol   string `yaml:"symbol"`
 		Decimals int32  `yaml:"decimals"`
 	}
+	// Token is an XRC20 token contract tracked as a second currency
+	Token struct {
+		Contract string `yaml:"contract"`
+		Symbol   string `yaml:"symbol"`
+		Decimals int32  `yaml:"decimals"`
+	}
 	Server struct {
+		// Mode is OnlineMode, the default, or OfflineMode in which no node
+		// is connected and only the offline construction endpoints work
+		Mode           string `yaml:"mode"`
 		Port           string `yaml:"port"`
 		Endpoint       string `yaml:"endpoint"`
 		SecureEndpoint bool   `yaml:"secureEndpoint"`
 		RosettaVersion string `yaml:"rosettaVersion"`
+		// Endpoints are the failover nodes of Endpoint
+		Endpoints           []string      `yaml:"endpoints"`
+		MaxHeightLag        uint64        `yaml:"maxHeightLag"`
+		HealthCheckInterval time.Duration `yaml:"healthCheckInterval"`
+		// Timeout is the deadline of node calls, MethodTimeouts overrides it
+		// by gRPC method name
+		Timeout        time.Duration            `yaml:"timeout"`
+		MethodTimeouts map[string]time.Duration `yaml:"methodTimeouts"`
+		// PrefetchWindow is the number of blocks read ahead when blocks are
+		// requested in order
+		PrefetchWindow uint64 `yaml:"prefetchWindow"`
+		// MaxBlockAge is how old the latest block of the node may be for it
+		// to be considered synced
+		MaxBlockAge time.Duration `yaml:"maxBlockAge"`
+	}
+	// Cache bounds the blocks kept in memory, blocks FinalityDepth below the
+	// tip are final and never invalidated
+	Cache struct {
+		Size          int    `yaml:"size"`
+		FinalityDepth uint64 `yaml:"finalityDepth"`
+	}
+	// Index is the local transaction index serving /search/transactions,
+	// it is disabled if DataDir is not set. PollInterval also paces the
+	// block events of /events/blocks, which are tracked in memory from the
+	// tip in any case
+	Index struct {
+		DataDir      string        `yaml:"dataDir"`
+		PollInterval time.Duration `yaml:"pollInterval"`
 	}
 	Config struct {
 		NetworkIdentifier NetworkIdentifier `yaml:"network_identifier"`
 		Currency          Currency          `yaml:"currency"`
+		Tokens            []Token           `yaml:"tokens"`
 		Server            Server            `yaml:"server"`
+		Cache             Cache             `yaml:"cache"`
+		Index             Index             `yaml:"index"`
 		KeepNoneTxAction  bool              `yaml:"keepNoneTxAction"`
+		GenesisPath       string            `yaml:"genesisPath"`
+		// Networks are served along with the network of the top level
+		// config, each one with its own nodes
+		Networks []*Config `yaml:"networks"`
 	}
 )
 
@@ -47,3 +100,71 @@ func New(path string) (cfg *Config, err error) {
 	}
 	return
 }
+
+// NetworkConfigs returns the configs of the served networks, the top level
+// one comes first if its network is set. The networks share the mode, the
+// port and the Rosetta version of the top level config, and its index unless
+// they have their own.
+func (cfg *Config) NetworkConfigs() []*Config {
+	var cfgs []*Config
+	if cfg.NetworkIdentifier.Network != "" {
+		cfgs = append(cfgs, cfg)
+	}
+	for _, network := range cfg.Networks {
+		network.Server.Mode = cfg.Server.Mode
+		network.Server.Port = cfg.Server.Port
+		if network.Server.RosettaVersion == "" {
+			network.Server.RosettaVersion = cfg.Server.RosettaVersion
+		}
+		if network.Index.DataDir == "" {
+			network.Index = cfg.Index
+		}
+		cfgs = append(cfgs, network)
+	}
+	return cfgs
+}
+
+// TokenByContract returns the configured XRC20 token of the contract address.
+func (cfg *Config) TokenByContract(contract string) (Token, bool) {
+	for _, token := range cfg.Tokens {
+		if token.Contract == contract {
+			return token, true
+		}
+	}
+	return Token{}, false
+}
+
+// EndpointList returns the node endpoints, Endpoint comes first.
+func (s Server) EndpointList() []string {
+	endpoints := make([]string, 0, len(s.Endpoints)+1)
+	seen := make(map[string]bool, len(s.Endpoints)+1)
+	for _, endpoint := range append([]string{s.Endpoint}, s.Endpoints...) {
+		if endpoint == "" || seen[endpoint] {
+			continue
+		}
+		seen[endpoint] = true
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints
+}
+
+// Offline tells if the gateway runs without nodes.
+func (s Server) Offline() bool {
+	return s.Mode == OfflineMode
+}
+
+// BlockAgeLimit returns how old the latest block of a synced node may be.
+func (s Server) BlockAgeLimit() time.Duration {
+	if s.MaxBlockAge <= 0 {
+		return defaultMaxBlockAge
+	}
+	return s.MaxBlockAge
+}
+
+// CallTimeout returns the deadline of the gRPC method, 0 means no deadline.
+func (s Server) CallTimeout(method string) time.Duration {
+	if timeout, ok := s.MethodTimeouts[method]; ok {
+		return timeout
+	}
+	return s.Timeout
+}
@@ -8,6 +8,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -28,3 +29,53 @@ func TestConfig(t *testing.T) {
 	r.Equal("1.4.2", cfg.Server.RosettaVersion)
 	r.Equal(false, cfg.KeepNoneTxAction)
 }
+
+func TestServer_EndpointList(t *testing.T) {
+	r := require.New(t)
+	tests := []struct {
+		server Server
+		expect []string
+	}{
+		{Server{Endpoint: "a"}, []string{"a"}},
+		{Server{Endpoint: "a", Endpoints: []string{"b", "a", "c"}}, []string{"a", "b", "c"}},
+		{Server{Endpoints: []string{"b", "c"}}, []string{"b", "c"}},
+		{Server{}, []string{}},
+	}
+	for i, test := range tests {
+		r.Equal(test.expect, test.server.EndpointList(), "index: %d", i)
+	}
+}
+
+func TestServer_CallTimeout(t *testing.T) {
+	r := require.New(t)
+	s := Server{
+		Timeout:        time.Minute,
+		MethodTimeouts: map[string]time.Duration{"GetRawBlocks": 2 * time.Minute},
+	}
+	r.Equal(2*time.Minute, s.CallTimeout("GetRawBlocks"))
+	r.Equal(time.Minute, s.CallTimeout("GetChainMeta"))
+	r.Zero(Server{}.CallTimeout("GetChainMeta"))
+}
+
+func TestConfig_NetworkConfigs(t *testing.T) {
+	r := require.New(t)
+	testnet := &Config{
+		NetworkIdentifier: NetworkIdentifier{Blockchain: "IoTeX", Network: "testnet", EvmNetworkID: 4690},
+		Server:            Server{Endpoint: "b", Port: "9090"},
+	}
+	cfg := &Config{
+		NetworkIdentifier: NetworkIdentifier{Blockchain: "IoTeX", Network: "mainnet", EvmNetworkID: 4689},
+		Server:            Server{Mode: OfflineMode, Endpoint: "a", Port: "8080", RosettaVersion: "1.4.2"},
+		Networks:          []*Config{testnet},
+	}
+	cfgs := cfg.NetworkConfigs()
+	r.Equal([]*Config{cfg, testnet}, cfgs)
+	r.True(testnet.Server.Offline())
+	r.Equal("8080", testnet.Server.Port)
+	r.Equal("1.4.2", testnet.Server.RosettaVersion)
+	r.Equal("b", testnet.Server.Endpoint)
+
+	// the top level config without a network only holds the shared settings
+	cfg.NetworkIdentifier = NetworkIdentifier{}
+	r.Equal([]*Config{testnet}, cfg.NetworkConfigs())
+}
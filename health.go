@@ -0,0 +1,66 @@
+// Copyright (c) 2020 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	ic "github.com/iotexproject/iotex-core-rosetta-gateway/iotex-client"
+)
+
+const (
+	LivenessPath  = "/healthz"
+	ReadinessPath = "/readyz"
+
+	readinessTimeout = 5 * time.Second
+)
+
+// HealthMiddleware answers the liveness and readiness probes and passes the
+// other requests on. The gateway is ready when the nodes of all the networks
+// are reachable and synced.
+func HealthMiddleware(clients []ic.IoTexClient, inner http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case LivenessPath:
+			w.Write([]byte("ok\n"))
+		case ReadinessPath:
+			for _, client := range clients {
+				if err := checkReadiness(r.Context(), client); err != nil {
+					http.Error(w, client.GetConfig().NetworkIdentifier.Network+": "+err.Error(), http.StatusServiceUnavailable)
+					return
+				}
+			}
+			w.Write([]byte("ok\n"))
+		default:
+			inner.ServeHTTP(w, r)
+		}
+	})
+}
+
+// checkReadiness fails if the node cannot be reached or its latest block is
+// older than the configured max block age, it never fails in offline mode.
+func checkReadiness(ctx context.Context, client ic.IoTexClient) error {
+	if client.GetConfig().Server.Offline() {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+	// the tip height comes from the node status, same as GetStatus
+	blk, err := client.GetLatestBlock(ctx)
+	if err != nil {
+		return errors.Wrap(err, "node is unreachable")
+	}
+	age := time.Since(time.Unix(0, blk.Timestamp*int64(time.Millisecond)))
+	if age > client.GetConfig().Server.BlockAgeLimit() {
+		return errors.Errorf("node is not synced, latest block %d is %s old", blk.BlockIdentifier.Index, age.Truncate(time.Second))
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2020 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core-rosetta-gateway/config"
+	ic "github.com/iotexproject/iotex-core-rosetta-gateway/iotex-client"
+	"github.com/iotexproject/iotex-core-rosetta-gateway/iotex-client/mock_client"
+)
+
+func TestHealthMiddleware(t *testing.T) {
+	var (
+		require = require.New(t)
+		ctrl    = gomock.NewController(t)
+		cli     = mock_client.NewMockIoTexClient(ctrl)
+		inner   = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		})
+		latest = func(age time.Duration) *types.Block {
+			return &types.Block{
+				BlockIdentifier: &types.BlockIdentifier{Index: 10, Hash: "block10"},
+				Timestamp:       time.Now().Add(-age).UnixNano() / int64(time.Millisecond),
+			}
+		}
+	)
+	cli.EXPECT().GetConfig().Return(testConfig()).AnyTimes()
+	handler := HealthMiddleware([]ic.IoTexClient{cli}, inner)
+
+	// the gateway is alive whatever the node
+	rec := serve(handler, LivenessPath, "")
+	require.Equal(http.StatusOK, rec.Code)
+	require.Equal("ok\n", rec.Body.String())
+
+	// and ready once the node is synced
+	cli.EXPECT().GetLatestBlock(gomock.Any()).Return(latest(time.Second), nil).Times(1)
+	rec = serve(handler, ReadinessPath, "")
+	require.Equal(http.StatusOK, rec.Code)
+	require.Equal("ok\n", rec.Body.String())
+
+	// an unreachable node is not ready
+	cli.EXPECT().GetLatestBlock(gomock.Any()).Return(nil, errors.New("connection refused")).Times(1)
+	rec = serve(handler, ReadinessPath, "")
+	require.Equal(http.StatusServiceUnavailable, rec.Code)
+	require.Equal("testnet: node is unreachable: connection refused\n", rec.Body.String())
+
+	// neither is a stale one
+	cli.EXPECT().GetLatestBlock(gomock.Any()).Return(latest(2*time.Minute), nil).Times(1)
+	rec = serve(handler, ReadinessPath, "")
+	require.Equal(http.StatusServiceUnavailable, rec.Code)
+	require.Equal("testnet: node is not synced, latest block 10 is 2m0s old\n", rec.Body.String())
+
+	// the other requests are passed on
+	rec = serve(handler, "/network/list", "{}")
+	require.Equal(http.StatusTeapot, rec.Code)
+}
+
+func TestCheckReadiness_Offline(t *testing.T) {
+	var (
+		require = require.New(t)
+		ctrl    = gomock.NewController(t)
+		cli     = mock_client.NewMockIoTexClient(ctrl)
+		cfg     = testConfig()
+	)
+	// the node is never asked in offline mode
+	cfg.Server.Mode = config.OfflineMode
+	cli.EXPECT().GetConfig().Return(cfg).AnyTimes()
+	require.NoError(checkReadiness(context.Background(), cli))
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2020 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package indexer
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/pkg/errors"
+
+	ic "github.com/iotexproject/iotex-core-rosetta-gateway/iotex-client"
+)
+
+const (
+	// maxEvents is the number of the last block events kept in memory
+	maxEvents = 10000
+	// maxTrackedBlocks is the number of the last blocks kept to detect the
+	// reorganizations, a deeper one restarts the events at the new block
+	maxTrackedBlocks = 1000
+)
+
+// EventTracker follows the tip of the node and records the blocks it adds and
+// removes as block events. It starts at the tip of the node, independently of
+// the transaction index, and keeps the events in memory: they start over when
+// the gateway restarts and only the last maxEvents of them are served.
+type EventTracker struct {
+	client       ic.IoTexClient
+	pollInterval time.Duration
+
+	mu sync.RWMutex
+	// events are the last recorded events, ordered by sequence
+	events []*types.BlockEvent
+	// blocks are the last blocks of the followed chain
+	blocks []*types.BlockIdentifier
+	// nextSequence is the sequence of the next event
+	nextSequence int64
+}
+
+// NewEventTracker creates the block event tracker of the network of the
+// client, it follows the node once it runs.
+func NewEventTracker(client ic.IoTexClient) *EventTracker {
+	t := &EventTracker{
+		client:       client,
+		pollInterval: client.GetConfig().Index.PollInterval,
+	}
+	if t.pollInterval <= 0 {
+		t.pollInterval = defaultPollInterval
+	}
+	return t
+}
+
+// Run follows the tip of the node until ctx is done.
+func (t *EventTracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.pollInterval)
+	defer ticker.Stop()
+	for {
+		if err := t.sync(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("failed to track the blocks of %s: %v\n", t.client.GetConfig().NetworkIdentifier.Network, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sync records the blocks up to the tip of the node. A block which does not
+// follow the last tracked one replaces it, the tracked block is removed first.
+func (t *EventTracker) sync(ctx context.Context) error {
+	latest, err := t.client.GetLatestBlock(ctx)
+	if err != nil {
+		return err
+	}
+	for {
+		tip := t.tip()
+		if tip == nil {
+			t.record(types.ADDED, latest.BlockIdentifier)
+			return nil
+		}
+		switch {
+		case tip.Index > latest.BlockIdentifier.Index:
+			// the node is behind the tracked chain, it is waited for
+			return nil
+		case tip.Index == latest.BlockIdentifier.Index:
+			if tip.Hash != latest.BlockIdentifier.Hash {
+				t.record(types.REMOVED, tip)
+				continue
+			}
+			return nil
+		}
+		blk, err := t.client.GetBlock(ctx, tip.Index+1)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get block %d", tip.Index+1)
+		}
+		if blk.ParentBlockIdentifier.Hash != tip.Hash {
+			t.record(types.REMOVED, tip)
+			continue
+		}
+		t.record(types.ADDED, blk.BlockIdentifier)
+	}
+}
+
+// tip returns the last tracked block, nil if no block is tracked.
+func (t *EventTracker) tip() *types.BlockIdentifier {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if len(t.blocks) == 0 {
+		return nil
+	}
+	return t.blocks[len(t.blocks)-1]
+}
+
+// record appends the event of the block and updates the tracked blocks.
+func (t *EventTracker) record(typ types.BlockEventType, block *types.BlockIdentifier) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, &types.BlockEvent{
+		Sequence:        t.nextSequence,
+		BlockIdentifier: block,
+		Type:            typ,
+	})
+	t.nextSequence++
+	if len(t.events) > maxEvents {
+		t.events = t.events[len(t.events)-maxEvents:]
+	}
+	if typ == types.REMOVED {
+		t.blocks = t.blocks[:len(t.blocks)-1]
+		return
+	}
+	t.blocks = append(t.blocks, block)
+	if len(t.blocks) > maxTrackedBlocks {
+		t.blocks = t.blocks[len(t.blocks)-maxTrackedBlocks:]
+	}
+}
+
+// Events returns the block events from the offset on, the last events are
+// returned if offset is nil. The events dropped from memory are skipped.
+func (t *EventTracker) Events(offset, limit *int64) (*types.EventsBlocksResponse, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	resp := &types.EventsBlocksResponse{Events: []*types.BlockEvent{}}
+	if len(t.events) == 0 {
+		return resp, nil
+	}
+	n := pageLimit(limit)
+	resp.MaxSequence = t.nextSequence - 1
+	start := resp.MaxSequence + 1 - n
+	if offset != nil {
+		start = *offset
+	}
+	first := t.events[0].Sequence
+	if start < first {
+		start = first
+	}
+	for i := start - first; i < int64(len(t.events)) && int64(len(resp.Events)) < n; i++ {
+		resp.Events = append(resp.Events, t.events[i])
+	}
+	return resp, nil
+}
@@ -0,0 +1,369 @@
+// Copyright (c) 2020 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package indexer
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+
+	ic "github.com/iotexproject/iotex-core-rosetta-gateway/iotex-client"
+)
+
+const (
+	defaultPollInterval = 5 * time.Second
+	// batchSize is the number of blocks written in one db transaction
+	batchSize = 100
+
+	// DefaultLimit and MaxLimit bound the transactions and the block events
+	// returned at once
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+var (
+	// blocksBucket maps the indexed heights to their block hash
+	blocksBucket = []byte("blocks")
+	// txsBucket maps the positions of the transactions to their records
+	txsBucket = []byte("txs")
+	// hashesBucket maps the transaction hashes to their position
+	hashesBucket = []byte("hashes")
+	// the keys of the buckets below are the indexed values followed by the
+	// positions of the transactions, their values are empty
+	addressesBucket = []byte("addresses")
+	typesBucket     = []byte("types")
+	statusesBucket  = []byte("statuses")
+
+	buckets = [][]byte{blocksBucket, txsBucket, hashesBucket, addressesBucket, typesBucket, statusesBucket}
+)
+
+type (
+	// Indexer follows the blocks of the node and indexes their transactions
+	// by hash, address, operation type and operation status.
+	Indexer struct {
+		client       ic.IoTexClient
+		db           *bolt.DB
+		pollInterval time.Duration
+	}
+
+	// record is an indexed transaction along with its block.
+	record struct {
+		Block       *types.BlockIdentifier `json:"block"`
+		Transaction *types.Transaction     `json:"transaction"`
+	}
+)
+
+// New opens the index of the network of the client in the configured data
+// directory.
+func New(client ic.IoTexClient) (*Indexer, error) {
+	cfg := client.GetConfig()
+	if cfg.Index.DataDir == "" {
+		return nil, errors.New("no index data directory is configured")
+	}
+	if err := os.MkdirAll(cfg.Index.DataDir, 0755); err != nil {
+		return nil, errors.Wrap(err, "failed to create the index data directory")
+	}
+	path := filepath.Join(cfg.Index.DataDir, cfg.NetworkIdentifier.Network+".db")
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open index %s", path)
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		for _, name := range buckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		db.Close()
+		return nil, errors.Wrap(err, "failed to init index")
+	}
+	idx := &Indexer{
+		client:       client,
+		db:           db,
+		pollInterval: cfg.Index.PollInterval,
+	}
+	if idx.pollInterval <= 0 {
+		idx.pollInterval = defaultPollInterval
+	}
+	return idx, nil
+}
+
+// Close closes the index.
+func (idx *Indexer) Close() error {
+	return idx.db.Close()
+}
+
+// Run indexes the new blocks of the node until ctx is done.
+func (idx *Indexer) Run(ctx context.Context) {
+	ticker := time.NewTicker(idx.pollInterval)
+	defer ticker.Stop()
+	for {
+		if err := idx.sync(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("failed to index the blocks of %s: %v\n", idx.client.GetConfig().NetworkIdentifier.Network, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tip returns the last indexed block, nil if no block is indexed yet.
+func (idx *Indexer) Tip() (tip *types.BlockIdentifier, err error) {
+	err = idx.db.View(func(tx *bolt.Tx) error {
+		tip = lastBlock(tx)
+		return nil
+	})
+	return
+}
+
+// sync indexes the blocks up to the tip of the node.
+func (idx *Indexer) sync(ctx context.Context) error {
+	latest, err := idx.client.GetLatestBlock(ctx)
+	if err != nil {
+		return err
+	}
+	for {
+		tip, err := idx.Tip()
+		if err != nil {
+			return err
+		}
+		height := int64(1)
+		if tip != nil {
+			height = tip.Index + 1
+		}
+		if height > latest.BlockIdentifier.Index {
+			return nil
+		}
+		blks := make([]*types.Block, 0, batchSize)
+		for ; height <= latest.BlockIdentifier.Index && len(blks) < batchSize; height++ {
+			blk, err := idx.client.GetBlock(ctx, height)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get block %d", height)
+			}
+			if blk.Transactions, err = idx.client.GetTransactions(ctx, height); err != nil {
+				return errors.Wrapf(err, "failed to get the transactions of block %d", height)
+			}
+			blks = append(blks, blk)
+		}
+		rolledBack, err := idx.index(blks)
+		if err != nil || rolledBack {
+			// the blocks are fetched again at the next poll
+			return err
+		}
+	}
+}
+
+// index writes the blocks following the tip of the index. If a block does not
+// follow the indexed one at its parent height, that block is rolled back and
+// the rest of blks is dropped.
+func (idx *Indexer) index(blks []*types.Block) (rolledBack bool, err error) {
+	err = idx.db.Update(func(tx *bolt.Tx) error {
+		blocks := tx.Bucket(blocksBucket)
+		for _, blk := range blks {
+			height := blk.BlockIdentifier.Index
+			if height > 1 {
+				parent := blocks.Get(heightKey(height - 1))
+				if parent != nil && string(parent) != blk.ParentBlockIdentifier.Hash {
+					rolledBack = true
+					return rollback(tx, height-1)
+				}
+			}
+			if err := blocks.Put(heightKey(height), []byte(blk.BlockIdentifier.Hash)); err != nil {
+				return err
+			}
+			for i, t := range blk.Transactions {
+				if err := putTransaction(tx, position(height, i), &record{
+					Block:       blk.BlockIdentifier,
+					Transaction: t,
+				}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	return
+}
+
+func putTransaction(tx *bolt.Tx, pos []byte, rec *record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if err := tx.Bucket(txsBucket).Put(pos, data); err != nil {
+		return err
+	}
+	if err := tx.Bucket(hashesBucket).Put([]byte(rec.Transaction.TransactionIdentifier.Hash), pos); err != nil {
+		return err
+	}
+	for name, values := range indexedValues(rec.Transaction) {
+		b := tx.Bucket([]byte(name))
+		for _, value := range values {
+			if err := b.Put(indexKey(value, pos), []byte{}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// rollback removes the blocks from the height on.
+func rollback(tx *bolt.Tx, height int64) error {
+	log.Printf("rolling back the index from block %d\n", height)
+	var (
+		blocks = tx.Bucket(blocksBucket)
+		txs    = tx.Bucket(txsBucket)
+		hashes = tx.Bucket(hashesBucket)
+		from   = heightKey(height)
+		keys   [][]byte
+		recs   = make(map[string]*record)
+	)
+	// the keys are collected first, a bolt cursor skips keys if the current
+	// one is deleted
+	c := txs.Cursor()
+	for k, v := c.Seek(from); k != nil; k, v = c.Next() {
+		rec := &record{}
+		if err := json.Unmarshal(v, rec); err != nil {
+			return err
+		}
+		recs[string(k)] = rec
+	}
+	for pos, rec := range recs {
+		for name, values := range indexedValues(rec.Transaction) {
+			b := tx.Bucket([]byte(name))
+			for _, value := range values {
+				if err := b.Delete(indexKey(value, []byte(pos))); err != nil {
+					return err
+				}
+			}
+		}
+		if err := hashes.Delete([]byte(rec.Transaction.TransactionIdentifier.Hash)); err != nil {
+			return err
+		}
+		if err := txs.Delete([]byte(pos)); err != nil {
+			return err
+		}
+	}
+	c = blocks.Cursor()
+	for k, _ := c.Seek(from); k != nil; k, _ = c.Next() {
+		keys = append(keys, append([]byte{}, k...))
+	}
+	for _, k := range keys {
+		if err := blocks.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexedValues returns the distinct values of the transaction by index
+// bucket.
+func indexedValues(t *types.Transaction) map[string][]string {
+	var (
+		values = make(map[string][]string)
+		seen   = make(map[string]bool)
+	)
+	add := func(bucket []byte, value string) {
+		key := string(bucket) + "/" + value
+		if value == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		values[string(bucket)] = append(values[string(bucket)], value)
+	}
+	for _, op := range t.Operations {
+		if op.Account != nil {
+			add(addressesBucket, op.Account.Address)
+		}
+		add(typesBucket, op.Type)
+		if op.Status != nil {
+			add(statusesBucket, *op.Status)
+		}
+	}
+	return values
+}
+
+func lastBlock(tx *bolt.Tx) *types.BlockIdentifier {
+	k, v := tx.Bucket(blocksBucket).Cursor().Last()
+	if k == nil {
+		return nil
+	}
+	return &types.BlockIdentifier{
+		Index: int64(binary.BigEndian.Uint64(k)),
+		Hash:  string(v),
+	}
+}
+
+// pageLimit returns the number of items of a page of the requested limit.
+func pageLimit(limit *int64) int64 {
+	if limit == nil {
+		return DefaultLimit
+	}
+	if *limit > MaxLimit {
+		return MaxLimit
+	}
+	return *limit
+}
+
+func heightKey(height int64) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, uint64(height))
+	return k
+}
+
+// position is the key of the transaction at the index of the block at the
+// height, the keys are ordered as the transactions are on the chain.
+func position(height int64, index int) []byte {
+	k := make([]byte, 12)
+	binary.BigEndian.PutUint64(k, uint64(height))
+	binary.BigEndian.PutUint32(k[8:], uint32(index))
+	return k
+}
+
+// indexKey is the key of the indexed value of the transaction at pos.
+func indexKey(value string, pos []byte) []byte {
+	return append(indexPrefix(value), pos...)
+}
+
+// indexPrefix is the common prefix of the keys of the indexed value, the
+// values do not contain the separator.
+func indexPrefix(value string) []byte {
+	return append([]byte(value), 0)
+}
+
+// descend calls fn with the positions under the prefix of the bucket, from the
+// last one before the end position down to the first one, until fn returns
+// false.
+func descend(b *bolt.Bucket, prefix, end []byte, fn func(pos []byte) (bool, error)) error {
+	c := b.Cursor()
+	k, _ := c.Seek(append(append([]byte{}, prefix...), end...))
+	if k == nil {
+		k, _ = c.Last()
+	} else {
+		k, _ = c.Prev()
+	}
+	for ; k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Prev() {
+		more, err := fn(k[len(prefix):])
+		if err != nil || !more {
+			return err
+		}
+	}
+	return nil
+}
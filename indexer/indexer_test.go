@@ -0,0 +1,263 @@
+// Copyright (c) 2020 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package indexer
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core-rosetta-gateway/config"
+	ic "github.com/iotexproject/iotex-core-rosetta-gateway/iotex-client"
+	"github.com/iotexproject/iotex-core-rosetta-gateway/iotex-client/mock_client"
+)
+
+func testBlock(height int64, fork string) *types.Block {
+	hash := func(h int64) string {
+		if h >= 3 {
+			return fork + strconv.FormatInt(h, 10)
+		}
+		return strconv.FormatInt(h, 10)
+	}
+	return &types.Block{
+		BlockIdentifier:       &types.BlockIdentifier{Index: height, Hash: hash(height)},
+		ParentBlockIdentifier: &types.BlockIdentifier{Index: height - 1, Hash: hash(height - 1)},
+	}
+}
+
+func testTransaction(hash, typ, status string, addrs ...string) *types.Transaction {
+	t := &types.Transaction{TransactionIdentifier: &types.TransactionIdentifier{Hash: hash}}
+	for _, addr := range addrs {
+		t.Operations = append(t.Operations, &types.Operation{
+			Type:    typ,
+			Status:  types.String(status),
+			Account: &types.AccountIdentifier{Address: addr},
+		})
+	}
+	return t
+}
+
+func searchHashes(resp *types.SearchTransactionsResponse) []string {
+	hashes := make([]string, 0, len(resp.Transactions))
+	for _, t := range resp.Transactions {
+		hashes = append(hashes, t.Transaction.TransactionIdentifier.Hash)
+	}
+	return hashes
+}
+
+func TestIndexer(t *testing.T) {
+	var (
+		require = require.New(t)
+		ctrl    = gomock.NewController(t)
+		cli     = mock_client.NewMockIoTexClient(ctrl)
+		cfg     = &config.Config{
+			NetworkIdentifier: config.NetworkIdentifier{Blockchain: "IoTeX", Network: "testnet"},
+			Index:             config.Index{DataDir: t.TempDir()},
+		}
+		txs = map[int64][]*types.Transaction{
+			1: {testTransaction("a", "transfer", ic.StatusSuccess, "io1", "io2")},
+			2: {
+				testTransaction("b", "transfer", ic.StatusFail, "io2", "io3"),
+				testTransaction("c", "stakeCreate", ic.StatusSuccess, "io3"),
+			},
+			3: {testTransaction("d", "transfer", ic.StatusSuccess, "io1", "io3")},
+		}
+		fork   = "x"
+		latest = int64(3)
+	)
+	cli.EXPECT().GetConfig().Return(cfg).AnyTimes()
+	cli.EXPECT().GetLatestBlock(gomock.Any()).DoAndReturn(func(context.Context) (*types.Block, error) {
+		return testBlock(latest, fork), nil
+	}).AnyTimes()
+	cli.EXPECT().GetBlock(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, height int64) (*types.Block, error) {
+		return testBlock(height, fork), nil
+	}).AnyTimes()
+	cli.EXPECT().GetTransactions(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, height int64) ([]*types.Transaction, error) {
+		return txs[height], nil
+	}).AnyTimes()
+
+	idx, err := New(cli)
+	require.NoError(err)
+	defer idx.Close()
+	require.NoError(idx.sync(context.Background()))
+	tip, err := idx.Tip()
+	require.NoError(err)
+	require.Equal(testBlock(3, fork).BlockIdentifier, tip)
+
+	or := types.OR
+	tests := []struct {
+		req    *types.SearchTransactionsRequest
+		expect []string
+	}{
+		{&types.SearchTransactionsRequest{}, []string{"d", "c", "b", "a"}},
+		{&types.SearchTransactionsRequest{Address: types.String("io1")}, []string{"d", "a"}},
+		{&types.SearchTransactionsRequest{
+			AccountIdentifier: &types.AccountIdentifier{Address: "io3"},
+			Type:              types.String("transfer"),
+		}, []string{"d", "b"}},
+		{&types.SearchTransactionsRequest{TransactionIdentifier: &types.TransactionIdentifier{Hash: "c"}}, []string{"c"}},
+		{&types.SearchTransactionsRequest{Success: types.Bool(false)}, []string{"b"}},
+		{&types.SearchTransactionsRequest{Success: types.Bool(true), Address: types.String("io2")}, []string{"a"}},
+		{&types.SearchTransactionsRequest{Status: types.String(ic.StatusSuccess), Type: types.String("stakeCreate")}, []string{"c"}},
+		{&types.SearchTransactionsRequest{
+			Operator: &or,
+			Address:  types.String("io1"),
+			Type:     types.String("stakeCreate"),
+		}, []string{"d", "c", "a"}},
+		{&types.SearchTransactionsRequest{Address: types.String("io3"), MaxBlock: types.Int64(2)}, []string{"c", "b"}},
+		{&types.SearchTransactionsRequest{TransactionIdentifier: &types.TransactionIdentifier{Hash: "d"}, MaxBlock: types.Int64(2)}, []string{}},
+		{&types.SearchTransactionsRequest{Address: types.String("io")}, []string{}},
+	}
+	for i, test := range tests {
+		resp, err := idx.Search(test.req)
+		require.NoError(err, "index: %d", i)
+		require.Equal(test.expect, searchHashes(resp), "index: %d", i)
+		require.EqualValues(len(test.expect), resp.TotalCount, "index: %d", i)
+		require.Nil(resp.NextOffset, "index: %d", i)
+	}
+
+	// pagination
+	resp, err := idx.Search(&types.SearchTransactionsRequest{Offset: types.Int64(1), Limit: types.Int64(2)})
+	require.NoError(err)
+	require.Equal([]string{"c", "b"}, searchHashes(resp))
+	require.EqualValues(4, resp.TotalCount)
+	require.EqualValues(3, *resp.NextOffset)
+	require.Equal(testBlock(2, fork).BlockIdentifier, resp.Transactions[0].BlockIdentifier)
+
+	// an empty page has no next one
+	resp, err = idx.Search(&types.SearchTransactionsRequest{Offset: types.Int64(1), Limit: types.Int64(0)})
+	require.NoError(err)
+	require.Empty(resp.Transactions)
+	require.EqualValues(4, resp.TotalCount)
+	require.Nil(resp.NextOffset)
+
+	// the scan stops after maxScan transactions, the page included
+	scan := maxScan
+	maxScan = 2
+	for _, req := range []*types.SearchTransactionsRequest{
+		{Limit: types.Int64(1)},
+		{Limit: types.Int64(1), Type: types.String("transfer"), Address: types.String("io3"), Operator: &or},
+	} {
+		resp, err = idx.Search(req)
+		require.NoError(err)
+		require.Equal([]string{"d"}, searchHashes(resp))
+		require.EqualValues(2, resp.TotalCount)
+		require.EqualValues(1, *resp.NextOffset)
+	}
+	// a search without a covering index does not reach the older ones
+	resp, err = idx.Search(&types.SearchTransactionsRequest{Offset: types.Int64(2), Limit: types.Int64(1), Success: types.Bool(true)})
+	require.NoError(err)
+	require.Empty(resp.Transactions)
+	require.EqualValues(2, resp.TotalCount)
+	require.Nil(resp.NextOffset)
+	maxScan = scan
+
+	// block 3 is replaced by the fork, its transactions are rolled back
+	fork = "y"
+	txs[3] = []*types.Transaction{testTransaction("e", "transfer", ic.StatusSuccess, "io4")}
+	latest = 4
+	require.NoError(idx.sync(context.Background()))
+	tip, err = idx.Tip()
+	require.NoError(err)
+	require.EqualValues(2, tip.Index)
+	resp, err = idx.Search(&types.SearchTransactionsRequest{Address: types.String("io1")})
+	require.NoError(err)
+	require.Equal([]string{"a"}, searchHashes(resp))
+	resp, err = idx.Search(&types.SearchTransactionsRequest{TransactionIdentifier: &types.TransactionIdentifier{Hash: "d"}})
+	require.NoError(err)
+	require.Zero(resp.TotalCount)
+
+	require.NoError(idx.sync(context.Background()))
+	tip, err = idx.Tip()
+	require.NoError(err)
+	require.Equal(testBlock(4, fork).BlockIdentifier, tip)
+	resp, err = idx.Search(&types.SearchTransactionsRequest{})
+	require.NoError(err)
+	require.Equal([]string{"e", "c", "b", "a"}, searchHashes(resp))
+}
+
+func TestEventTracker(t *testing.T) {
+	var (
+		require = require.New(t)
+		ctrl    = gomock.NewController(t)
+		cli     = mock_client.NewMockIoTexClient(ctrl)
+		cfg     = &config.Config{
+			NetworkIdentifier: config.NetworkIdentifier{Blockchain: "IoTeX", Network: "testnet"},
+		}
+		fork   = "x"
+		latest = int64(2)
+	)
+	cli.EXPECT().GetConfig().Return(cfg).AnyTimes()
+	cli.EXPECT().GetLatestBlock(gomock.Any()).DoAndReturn(func(context.Context) (*types.Block, error) {
+		return testBlock(latest, fork), nil
+	}).AnyTimes()
+	cli.EXPECT().GetBlock(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, height int64) (*types.Block, error) {
+		return testBlock(height, fork), nil
+	}).AnyTimes()
+
+	tracker := NewEventTracker(cli)
+	resp, err := tracker.Events(nil, nil)
+	require.NoError(err)
+	require.Zero(resp.MaxSequence)
+	require.Empty(resp.Events)
+
+	// the events start at the tip of the node, without the transactions
+	require.NoError(tracker.sync(context.Background()))
+	latest = 3
+	require.NoError(tracker.sync(context.Background()))
+	// block 3 is replaced by the fork
+	fork = "y"
+	latest = 4
+	require.NoError(tracker.sync(context.Background()))
+	// and both blocks again by a fork of the same height
+	fork = "z"
+	require.NoError(tracker.sync(context.Background()))
+	// a node behind the tracked chain adds nothing
+	latest = 3
+	require.NoError(tracker.sync(context.Background()))
+
+	event := func(seq int64, typ types.BlockEventType, blk *types.Block) *types.BlockEvent {
+		return &types.BlockEvent{Sequence: seq, BlockIdentifier: blk.BlockIdentifier, Type: typ}
+	}
+	all := []*types.BlockEvent{
+		event(0, types.ADDED, testBlock(2, "x")),
+		event(1, types.ADDED, testBlock(3, "x")),
+		event(2, types.REMOVED, testBlock(3, "x")),
+		event(3, types.ADDED, testBlock(3, "y")),
+		event(4, types.ADDED, testBlock(4, "y")),
+		event(5, types.REMOVED, testBlock(4, "y")),
+		event(6, types.REMOVED, testBlock(3, "y")),
+		event(7, types.ADDED, testBlock(3, "z")),
+		event(8, types.ADDED, testBlock(4, "z")),
+	}
+	tests := []struct {
+		offset, limit *int64
+		expect        []*types.BlockEvent
+	}{
+		{nil, nil, all},
+		{types.Int64(0), types.Int64(2), all[:2]},
+		{types.Int64(3), types.Int64(2), all[3:5]},
+		{nil, types.Int64(1), all[8:]},
+		{types.Int64(9), nil, []*types.BlockEvent{}},
+	}
+	for i, test := range tests {
+		resp, err := tracker.Events(test.offset, test.limit)
+		require.NoError(err, "index: %d", i)
+		require.EqualValues(8, resp.MaxSequence, "index: %d", i)
+		require.Equal(test.expect, resp.Events, "index: %d", i)
+	}
+
+	// the events dropped from memory are skipped
+	tracker.events = tracker.events[4:]
+	resp, err = tracker.Events(types.Int64(0), types.Int64(2))
+	require.NoError(err)
+	require.Equal(all[4:6], resp.Events)
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2020 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package indexer
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	bolt "go.etcd.io/bbolt"
+
+	ic "github.com/iotexproject/iotex-core-rosetta-gateway/iotex-client"
+)
+
+// maxScan bounds the transactions scanned by a search.
+var maxScan int64 = 10000
+
+// condition is a search condition on the transactions. The matching
+// transactions can be looked up by value in the bucket of an indexed
+// condition, the bucket is nil if the condition is not indexed.
+type condition struct {
+	bucket []byte
+	value  string
+	match  func(*types.Transaction) bool
+}
+
+// Search returns the transactions matching the conditions of the request, the
+// most recent first, along with the count of all the matching ones. The coin
+// identifier condition is ignored.
+//
+// Unless all the conditions must hold and one of them is indexed, the search
+// scans every transaction up to the max block and decodes it to check the
+// conditions. The scan stops after maxScan transactions, so the older
+// matching transactions of a search without a covering index are not found,
+// and the count is then the lower bound of the matching transactions.
+func (idx *Indexer) Search(req *types.SearchTransactionsRequest) (*types.SearchTransactionsResponse, error) {
+	var (
+		conds  = conditions(req)
+		or     = req.Operator != nil && *req.Operator == types.OR
+		offset int64
+		limit  = pageLimit(req.Limit)
+		resp   = &types.SearchTransactionsResponse{Transactions: []*types.BlockTransaction{}}
+	)
+	if req.Offset != nil {
+		offset = *req.Offset
+	}
+	err := idx.db.View(func(tx *bolt.Tx) error {
+		tip := lastBlock(tx)
+		if tip == nil {
+			return nil
+		}
+		maxBlock := tip.Index
+		if req.MaxBlock != nil && *req.MaxBlock < maxBlock {
+			maxBlock = *req.MaxBlock
+		}
+		// the positions of the blocks up to maxBlock are before end
+		end := heightKey(maxBlock + 1)
+		txs := tx.Bucket(txsBucket)
+		var scanned int64
+		visit := func(pos []byte) (bool, error) {
+			if scanned >= maxScan {
+				return false, nil
+			}
+			scanned++
+			inPage := resp.TotalCount >= offset && resp.TotalCount < offset+limit
+			// every transaction matches without conditions, only the
+			// returned ones are decoded
+			if len(conds) == 0 && !inPage {
+				resp.TotalCount++
+				return true, nil
+			}
+			rec := &record{}
+			if err := json.Unmarshal(txs.Get(pos), rec); err != nil {
+				return false, err
+			}
+			if !matches(rec.Transaction, conds, or) {
+				return true, nil
+			}
+			if inPage {
+				resp.Transactions = append(resp.Transactions, &types.BlockTransaction{
+					BlockIdentifier: rec.Block,
+					Transaction:     rec.Transaction,
+				})
+			}
+			resp.TotalCount++
+			return true, nil
+		}
+
+		// the transactions are scanned unless all the conditions must hold
+		// and one of them is indexed
+		if !or {
+			for _, cond := range conds {
+				switch {
+				case cond.bucket == nil:
+					continue
+				case bytes.Equal(cond.bucket, hashesBucket):
+					pos := tx.Bucket(hashesBucket).Get([]byte(cond.value))
+					if pos == nil || bytes.Compare(pos, end) >= 0 {
+						return nil
+					}
+					_, err := visit(pos)
+					return err
+				default:
+					return descend(tx.Bucket(cond.bucket), indexPrefix(cond.value), end, visit)
+				}
+			}
+		}
+		return descend(txs, nil, end, visit)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// a page without transactions has no next one, or the search would never
+	// end with a limit of 0
+	if next := offset + int64(len(resp.Transactions)); len(resp.Transactions) > 0 && next < resp.TotalCount {
+		resp.NextOffset = &next
+	}
+	return resp, nil
+}
+
+// conditions returns the conditions of the request, the transaction hash comes
+// first as it is the most selective one.
+func conditions(req *types.SearchTransactionsRequest) []*condition {
+	var conds []*condition
+	if ti := req.TransactionIdentifier; ti != nil {
+		conds = append(conds, &condition{
+			bucket: hashesBucket,
+			value:  ti.Hash,
+			match: func(t *types.Transaction) bool {
+				return t.TransactionIdentifier.Hash == ti.Hash
+			},
+		})
+	}
+	if account := req.AccountIdentifier; account != nil {
+		conds = append(conds, &condition{
+			bucket: addressesBucket,
+			value:  account.Address,
+			match: anyOperation(func(op *types.Operation) bool {
+				return op.Account != nil && types.Hash(op.Account) == types.Hash(account)
+			}),
+		})
+	}
+	if req.Address != nil {
+		address := *req.Address
+		conds = append(conds, &condition{
+			bucket: addressesBucket,
+			value:  address,
+			match: anyOperation(func(op *types.Operation) bool {
+				return op.Account != nil && op.Account.Address == address
+			}),
+		})
+	}
+	if req.Type != nil {
+		typ := *req.Type
+		conds = append(conds, &condition{
+			bucket: typesBucket,
+			value:  typ,
+			match: anyOperation(func(op *types.Operation) bool {
+				return op.Type == typ
+			}),
+		})
+	}
+	if req.Status != nil {
+		conds = append(conds, statusCondition(*req.Status))
+	}
+	if req.Success != nil {
+		// a transaction fails if any of its operations failed
+		failed := statusCondition(ic.StatusFail)
+		if *req.Success {
+			failed.bucket = nil
+			match := failed.match
+			failed.match = func(t *types.Transaction) bool {
+				return !match(t)
+			}
+		}
+		conds = append(conds, failed)
+	}
+	if currency := req.Currency; currency != nil {
+		conds = append(conds, &condition{
+			match: anyOperation(func(op *types.Operation) bool {
+				return op.Amount != nil && op.Amount.Currency != nil &&
+					op.Amount.Currency.Symbol == currency.Symbol &&
+					op.Amount.Currency.Decimals == currency.Decimals
+			}),
+		})
+	}
+	return conds
+}
+
+func statusCondition(status string) *condition {
+	return &condition{
+		bucket: statusesBucket,
+		value:  status,
+		match: anyOperation(func(op *types.Operation) bool {
+			return op.Status != nil && *op.Status == status
+		}),
+	}
+}
+
+func anyOperation(fn func(*types.Operation) bool) func(*types.Transaction) bool {
+	return func(t *types.Transaction) bool {
+		for _, op := range t.Operations {
+			if fn(op) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// matches tells if any of the conditions holds for the transaction with the
+// or operator, all of them otherwise.
+func matches(t *types.Transaction, conds []*condition, or bool) bool {
+	if len(conds) == 0 {
+		return true
+	}
+	for _, cond := range conds {
+		if cond.match(t) == or {
+			return or
+		}
+	}
+	return !or
+}
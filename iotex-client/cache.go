@@ -0,0 +1,273 @@
+// Copyright (c) 2020 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package iotex_client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/iotexproject/iotex-core-rosetta-gateway/config"
+)
+
+const (
+	defaultCacheSize     = 1000
+	defaultFinalityDepth = 10
+)
+
+type (
+	// cachedIoTexClient is an IoTexClient which keeps the assembled blocks and
+	// their transactions in LRU caches. Blocks within the finality depth of
+	// the tip are checked against the node before they are served, and
+	// dropped once a block links to a different parent, blocks below it are
+	// final and never invalidated.
+	cachedIoTexClient struct {
+		IoTexClient
+
+		mu            sync.Mutex
+		finalityDepth int64
+		// tip is the highest block height seen so far
+		tip     int64
+		blocks  *lru.Cache // height to *types.Block
+		heights *lru.Cache // block hash to height
+		txs     *lru.Cache // height to *cachedTransactions
+		// onInvalidate is told the height the chain was reorganized from
+		onInvalidate func(from int64)
+	}
+
+	// cachedTransactions are the transactions of the block with the hash.
+	cachedTransactions struct {
+		hash string
+		txs  []*types.Transaction
+	}
+)
+
+var _ IoTexClient = (*cachedIoTexClient)(nil)
+
+func newCachedIoTexClient(cli IoTexClient, cfg config.Cache) (*cachedIoTexClient, error) {
+	size := cfg.Size
+	if size <= 0 {
+		size = defaultCacheSize
+	}
+	finalityDepth := cfg.FinalityDepth
+	if finalityDepth == 0 {
+		finalityDepth = defaultFinalityDepth
+	}
+	c := &cachedIoTexClient{
+		IoTexClient:   cli,
+		finalityDepth: int64(finalityDepth),
+	}
+	var err error
+	if c.blocks, err = lru.New(size); err != nil {
+		return nil, err
+	}
+	if c.heights, err = lru.New(size); err != nil {
+		return nil, err
+	}
+	if c.txs, err = lru.New(size); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// GetBlock serves the final blocks from the cache, the others are asked to
+// the node in case they were reorganized.
+func (c *cachedIoTexClient) GetBlock(ctx context.Context, height int64) (*types.Block, error) {
+	c.mu.Lock()
+	blk, ok := c.cachedBlock(height)
+	ok = ok && c.isFinal(height)
+	c.mu.Unlock()
+	observeCacheLookup("block", ok)
+	if ok {
+		return copyBlock(blk), nil
+	}
+	blk, err := c.IoTexClient.GetBlock(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	c.addBlock(blk)
+	return blk, nil
+}
+
+func (c *cachedIoTexClient) GetBlockByHash(ctx context.Context, hash string) (*types.Block, error) {
+	c.mu.Lock()
+	var (
+		blk *types.Block
+		ok  bool
+	)
+	if height, found := c.heights.Get(hash); found {
+		blk, ok = c.cachedBlock(height.(int64))
+		ok = ok && blk.BlockIdentifier.Hash == hash
+	}
+	c.mu.Unlock()
+	observeCacheLookup("block", ok)
+	if ok {
+		return copyBlock(blk), nil
+	}
+	blk, err := c.IoTexClient.GetBlockByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	c.addBlock(blk)
+	return blk, nil
+}
+
+// GetLatestBlock always asks the node, the new tip is checked against the
+// cached blocks for reorganizations.
+func (c *cachedIoTexClient) GetLatestBlock(ctx context.Context) (*types.Block, error) {
+	blk, err := c.IoTexClient.GetLatestBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.addBlock(blk)
+	return blk, nil
+}
+
+func (c *cachedIoTexClient) GetGenesisBlock(ctx context.Context) (*types.Block, error) {
+	return c.GetBlock(ctx, genesisHeight)
+}
+
+// GetTransactions caches the transactions of a height only along with its
+// block, so that they are invalidated together. The transactions of a block
+// which is not final are served only if the node still has the block.
+func (c *cachedIoTexClient) GetTransactions(ctx context.Context, height int64) ([]*types.Transaction, error) {
+	c.mu.Lock()
+	final := c.isFinal(height)
+	blk, known := c.peekBlock(height)
+	c.mu.Unlock()
+	if !final {
+		var err error
+		if blk, err = c.GetBlock(ctx, height); err != nil {
+			return nil, err
+		}
+		known = true
+	}
+	c.mu.Lock()
+	cached, ok := c.txs.Get(height)
+	c.mu.Unlock()
+	if ok && (final || cached.(*cachedTransactions).hash == blk.BlockIdentifier.Hash) {
+		observeCacheLookup("transactions", true)
+		return copyTransactions(cached.(*cachedTransactions).txs), nil
+	}
+	observeCacheLookup("transactions", false)
+
+	txs, err := c.IoTexClient.GetTransactions(ctx, height)
+	if err != nil || !known {
+		return txs, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// the block may have been replaced in the meantime
+	if cur, ok := c.peekBlock(height); ok && cur.BlockIdentifier.Hash == blk.BlockIdentifier.Hash {
+		c.txs.Add(height, &cachedTransactions{hash: blk.BlockIdentifier.Hash, txs: copyTransactions(txs)})
+	}
+	return txs, nil
+}
+
+// addBlock caches the block fetched from the node, the cached blocks which
+// don't link to it are invalidated.
+func (c *cachedIoTexClient) addBlock(blk *types.Block) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	height := blk.BlockIdentifier.Index
+	if height > c.tip {
+		c.tip = height
+	}
+	if c.isFinal(height) && c.blocks.Contains(height) {
+		return
+	}
+	if parent, ok := c.peekBlock(height - 1); ok && parent.BlockIdentifier.Hash != blk.ParentBlockIdentifier.Hash {
+		c.invalidate(height - 1)
+	} else if cached, ok := c.peekBlock(height); ok && cached.BlockIdentifier.Hash != blk.BlockIdentifier.Hash {
+		c.invalidate(height)
+	} else if child, ok := c.peekBlock(height + 1); ok && child.ParentBlockIdentifier.Hash != blk.BlockIdentifier.Hash {
+		c.invalidate(height + 1)
+	}
+	c.blocks.Add(height, copyBlock(blk))
+	c.heights.Add(blk.BlockIdentifier.Hash, height)
+}
+
+// invalidate drops the blocks and transactions from the height on, except the
+// final ones.
+func (c *cachedIoTexClient) invalidate(from int64) {
+	if c.onInvalidate != nil {
+		c.onInvalidate(from)
+	}
+	for _, key := range c.blocks.Keys() {
+		height := key.(int64)
+		if height < from || c.isFinal(height) {
+			continue
+		}
+		if blk, ok := c.peekBlock(height); ok {
+			c.heights.Remove(blk.BlockIdentifier.Hash)
+		}
+		c.blocks.Remove(height)
+	}
+	for _, key := range c.txs.Keys() {
+		if height := key.(int64); height >= from && !c.isFinal(height) {
+			c.txs.Remove(height)
+		}
+	}
+}
+
+func (c *cachedIoTexClient) isFinal(height int64) bool {
+	return height <= c.tip-c.finalityDepth
+}
+
+// cachedBlock returns the cached block of the height and marks it as recently
+// used.
+func (c *cachedIoTexClient) cachedBlock(height int64) (*types.Block, bool) {
+	blk, ok := c.blocks.Get(height)
+	if !ok {
+		return nil, false
+	}
+	return blk.(*types.Block), true
+}
+
+// peekBlock returns the cached block of the height without updating its
+// recency.
+func (c *cachedIoTexClient) peekBlock(height int64) (*types.Block, bool) {
+	blk, ok := c.blocks.Peek(height)
+	if !ok {
+		return nil, false
+	}
+	return blk.(*types.Block), true
+}
+
+// copyBlock returns a shallow copy of the block, callers fill in the
+// transactions of the returned block.
+func copyBlock(blk *types.Block) *types.Block {
+	cp := *blk
+	return &cp
+}
+
+// copyTransactions returns a copy of the transactions down to the accounts
+// and amounts of their operations, so that callers can't alter the cached
+// ones.
+func copyTransactions(txs []*types.Transaction) []*types.Transaction {
+	ret := make([]*types.Transaction, 0, len(txs))
+	for _, tx := range txs {
+		cp := *tx
+		cp.Operations = make([]*types.Operation, 0, len(tx.Operations))
+		for _, op := range tx.Operations {
+			opCp := *op
+			if op.Account != nil {
+				account := *op.Account
+				opCp.Account = &account
+			}
+			if op.Amount != nil {
+				amount := *op.Amount
+				opCp.Amount = &amount
+			}
+			cp.Operations = append(cp.Operations, &opCp)
+		}
+		ret = append(ret, &cp)
+	}
+	return ret
+}
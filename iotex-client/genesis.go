@@ -0,0 +1,57 @@
+// Copyright (c) 2020 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package iotex_client
+
+import (
+	"encoding/hex"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/iotexproject/iotex-core/blockchain/genesis"
+	"github.com/pkg/errors"
+)
+
+const (
+	// GenesisAllocationType is the operation type of the initial balances
+	// allocated in the genesis configuration.
+	GenesisAllocationType = "GENESIS_ALLOCATION"
+
+	// genesisHeight is the height of the block which carries the allocations.
+	genesisHeight = 1
+)
+
+// genesisTransaction returns a synthetic transaction with the initial balance
+// allocations of the genesis configuration, identified by the config hash.
+func (c *grpcIoTexClient) genesisTransaction(path string) (*types.Transaction, error) {
+	g, err := genesis.New(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load genesis config")
+	}
+	h := g.Hash()
+	ret := &types.Transaction{TransactionIdentifier: &types.TransactionIdentifier{Hash: hex.EncodeToString(h[:])}}
+	addrs, amounts := g.InitBalances()
+	ret.Operations = make([]*types.Operation, 0, len(addrs))
+	for i, addr := range addrs {
+		ret.Operations = append(ret.Operations, &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: int64(i),
+			},
+			Type:   GenesisAllocationType,
+			Status: types.String(StatusSuccess),
+			Account: &types.AccountIdentifier{
+				Address: addr.String(),
+			},
+			Amount: &types.Amount{
+				Value: amounts[i].String(),
+				Currency: &types.Currency{
+					Symbol:   c.cfg.Currency.Symbol,
+					Decimals: c.cfg.Currency.Decimals,
+				},
+			},
+		})
+	}
+	return ret, nil
+}
@@ -8,28 +8,34 @@ package iotex_client
 
 import (
 	"context"
-	"crypto/tls"
 	"encoding/hex"
 	"log"
 	"math/big"
 	"sync"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
-	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/connectivity"
-	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
-	"github.com/iotexproject/go-pkgs/hash"
 	"github.com/iotexproject/iotex-address/address"
-	"github.com/iotexproject/iotex-core/action"
 	"github.com/iotexproject/iotex-proto/golang/iotexapi"
 	"github.com/iotexproject/iotex-proto/golang/iotextypes"
 
 	"github.com/iotexproject/iotex-core-rosetta-gateway/config"
 )
 
+var (
+	// ErrHistoricalStateUnavailable is returned when the node cannot serve
+	// state at the requested height.
+	ErrHistoricalStateUnavailable = errors.New("historical state is not available on the node")
+	// ErrOfflineMode is returned by the calls to the node in offline mode.
+	ErrOfflineMode = errors.New("no node is connected in offline mode")
+	// ErrUnknownSubAccount is returned for the sub-accounts which are neither
+	// the rewards nor the staking ones.
+	ErrUnknownSubAccount = errors.New("unknown sub-account")
+)
+
 type (
 	// IoTexClient is the IoTex blockchain client interface.
 	IoTexClient interface {
@@ -40,6 +46,9 @@ type (
 		// GetBlock returns the IoTex block at given height.
 		GetBlock(ctx context.Context, height int64) (*types.Block, error)
 
+		// GetBlockByHash returns the IoTex block with given hash.
+		GetBlockByHash(ctx context.Context, hash string) (*types.Block, error)
+
 		// GetLatestBlock returns latest IoTex block.
 		GetLatestBlock(ctx context.Context) (*types.Block, error)
 
@@ -47,12 +56,24 @@ type (
 		GetGenesisBlock(ctx context.Context) (*types.Block, error)
 
 		// GetAccount returns the IoTex staking account for given owner address
-		// at given height.
-		GetAccount(ctx context.Context, height int64, owner string) (*types.AccountBalanceResponse, error)
+		// at given height, 0 means the latest height. The balances of the
+		// XRC20 tokens of currencies, all of them if currencies is empty, are
+		// returned as well; a token whose balance fails to be read is left out
+		// unless it is one of currencies.
+		GetAccount(ctx context.Context, height int64, owner string, currencies []*types.Currency) (*types.AccountBalanceResponse, error)
+
+		// GetSubAccount returns the balance of the sub-account of the owner at
+		// given height, 0 means the latest height. The sub-account is rewards
+		// or the one of a bucket.
+		GetSubAccount(ctx context.Context, height int64, owner, subAccount string) (*types.AccountBalanceResponse, error)
 
 		// SubmitTx submits the given encoded transaction to the node.
 		SubmitTx(ctx context.Context, tx *iotextypes.Action) (txid string, err error)
 
+		// GetTargetHeight returns the highest tip height of the healthy
+		// nodes, 0 if no node is healthy.
+		GetTargetHeight() (int64, error)
+
 		// GetStatus returns the status overview of the node.
 		GetStatus(ctx context.Context) (*iotexapi.GetChainMetaResponse, error)
 
@@ -69,11 +90,18 @@ type (
 
 		EstimateGasForAction(ctx context.Context, action *iotextypes.Action) (uint64, error)
 
+		// EstimateExecutionGas estimates the gas of the execution sent by caller.
+		EstimateExecutionGas(ctx context.Context, caller string, execution *iotextypes.Execution) (uint64, error)
+
 		GetBlockTransaction(ctx context.Context, actionHash string) (*types.Transaction, error)
 
 		GetMemPool(ctx context.Context, actionHashes []string) ([]*types.TransactionIdentifier, error)
 
 		GetMemPoolTransaction(ctx context.Context, h string) (*types.Transaction, error)
+
+		// Close stops the health checks of the nodes and closes the
+		// connections to them.
+		Close() error
 	}
 )
 
@@ -82,9 +110,12 @@ type (
 	grpcIoTexClient struct {
 		sync.RWMutex
 
-		grpcConn *grpc.ClientConn
+		pool     *endpointPool
 		client   iotexapi.APIServiceClient
 		cfg      *config.Config
+		prefetch *prefetcher
+		// genesisTx carries the genesis allocations, nil if not configured
+		genesisTx *types.Transaction
 	}
 )
 
@@ -101,7 +132,24 @@ type (
 
 // NewIoTexClient returns an implementation of IoTexClient
 func NewIoTexClient(cfg *config.Config) (cli IoTexClient, err error) {
-	return &grpcIoTexClient{cfg: cfg}, nil
+	c := &grpcIoTexClient{
+		cfg:      cfg,
+		prefetch: newPrefetcher(cfg.Server.PrefetchWindow),
+	}
+	if cfg.GenesisPath != "" {
+		if c.genesisTx, err = c.genesisTransaction(cfg.GenesisPath); err != nil {
+			return nil, err
+		}
+	}
+	cached, err := newCachedIoTexClient(c, cfg.Cache)
+	if err != nil {
+		return nil, err
+	}
+	// the blocks read ahead are reorganized along with the cached ones
+	cached.onInvalidate = func(from int64) {
+		c.prefetch.invalidate(uint64(from))
+	}
+	return cached, nil
 }
 
 func (c *grpcIoTexClient) GetChainID(ctx context.Context) (string, error) {
@@ -112,9 +160,21 @@ func (c *grpcIoTexClient) GetBlock(ctx context.Context, height int64) (ret *type
 	if err = c.connect(); err != nil {
 		return
 	}
+	if height > 0 {
+		if blk, ok := c.prefetch.block(ctx, c.client, uint64(height)); ok {
+			return genBlock(blk.parent, blk.meta), nil
+		}
+	}
 	return c.getBlock(ctx, height)
 }
 
+func (c *grpcIoTexClient) GetBlockByHash(ctx context.Context, hash string) (ret *types.Block, err error) {
+	if err = c.connect(); err != nil {
+		return
+	}
+	return c.getBlockByHash(ctx, hash)
+}
+
 func (c *grpcIoTexClient) GetLatestBlock(ctx context.Context) (*types.Block, error) {
 	if err := c.connect(); err != nil {
 		return nil, err
@@ -123,7 +183,7 @@ func (c *grpcIoTexClient) GetLatestBlock(ctx context.Context) (*types.Block, err
 }
 
 func (c *grpcIoTexClient) getLatestBlock(ctx context.Context) (*types.Block, error) {
-	res, err := c.client.GetChainMeta(context.Background(), &iotexapi.GetChainMetaRequest{})
+	res, err := c.client.GetChainMeta(ctx, &iotexapi.GetChainMetaRequest{})
 	if err != nil {
 		return nil, err
 	}
@@ -153,7 +213,18 @@ func (c *grpcIoTexClient) EstimateGasForAction(ctx context.Context, action *iote
 	return response.GetGas(), err
 }
 
-func (c *grpcIoTexClient) GetAccount(ctx context.Context, height int64, owner string) (ret *types.AccountBalanceResponse, err error) {
+func (c *grpcIoTexClient) EstimateExecutionGas(ctx context.Context, caller string, execution *iotextypes.Execution) (uint64, error) {
+	if err := c.connect(); err != nil {
+		return 0, err
+	}
+	response, err := c.client.EstimateActionGasConsumption(ctx, &iotexapi.EstimateActionGasConsumptionRequest{
+		Action:        &iotexapi.EstimateActionGasConsumptionRequest_Execution{Execution: execution},
+		CallerAddress: caller,
+	})
+	return response.GetGas(), err
+}
+
+func (c *grpcIoTexClient) GetAccount(ctx context.Context, height int64, owner string, currencies []*types.Currency) (ret *types.AccountBalanceResponse, err error) {
 	if err = c.connect(); err != nil {
 		return
 	}
@@ -165,6 +236,10 @@ func (c *grpcIoTexClient) GetAccount(ctx context.Context, height int64, owner st
 	}
 	acc := resp.GetAccountMeta()
 	blk := resp.GetBlockIdentifier()
+	// the node only serves the account state at its tip
+	if height > 0 && uint64(height) != blk.GetHeight() {
+		return nil, ErrHistoricalStateUnavailable
+	}
 	ret = &types.AccountBalanceResponse{
 		BlockIdentifier: &types.BlockIdentifier{
 			Index: int64(blk.GetHeight()),
@@ -179,33 +254,147 @@ func (c *grpcIoTexClient) GetAccount(ctx context.Context, height int64, owner st
 			}}},
 		Metadata: map[string]interface{}{NonceKey: acc.GetPendingNonce()},
 	}
+	requested := make(map[string]bool, len(currencies))
+	for _, currency := range currencies {
+		requested[types.Hash(currency)] = true
+	}
+	for _, token := range c.cfg.Tokens {
+		currency := TokenCurrency(token)
+		if len(currencies) > 0 && !requested[types.Hash(currency)] {
+			continue
+		}
+		var balance *big.Int
+		balance, err = c.getTokenBalance(ctx, token.Contract, owner)
+		if err != nil {
+			// a token which fails only hides its own balance unless it is
+			// explicitly requested
+			if requested[types.Hash(currency)] {
+				return nil, err
+			}
+			log.Printf("failed to get the %s balance of %s: %v\n", token.Symbol, owner, err)
+			err = nil
+			continue
+		}
+		ret.Balances = append(ret.Balances, &types.Amount{
+			Value:    balance.String(),
+			Currency: currency,
+		})
+	}
 	return
 }
 
-func (c *grpcIoTexClient) GetTransactions(ctx context.Context, height int64) (ret []*types.Transaction, err error) {
-	ret = make([]*types.Transaction, 0)
+func (c *grpcIoTexClient) GetSubAccount(ctx context.Context, height int64, owner, subAccount string) (ret *types.AccountBalanceResponse, err error) {
 	if err = c.connect(); err != nil {
 		return
 	}
-	actionMap, _, hashSlice, err := c.getRawBlock(ctx, height)
+	var (
+		balance *big.Int
+		blk     *iotextypes.BlockIdentifier
+	)
+	if subAccount == RewardsSubAccount {
+		balance, blk, err = c.unclaimedBalance(ctx, owner)
+	} else {
+		balance, blk, err = c.stakingBalance(ctx, owner, subAccount)
+	}
 	if err != nil {
 		return
 	}
-	// get TransactionLog by height,if log is not exist,the err will be nil
-	transferLogMap, err := getTransactionLog(ctx, height, c.client)
+	// the node only serves the state at its tip
+	if height > 0 && uint64(height) != blk.GetHeight() {
+		return nil, ErrHistoricalStateUnavailable
+	}
+	ret = &types.AccountBalanceResponse{
+		BlockIdentifier: &types.BlockIdentifier{
+			Index: int64(blk.GetHeight()),
+			Hash:  blk.GetHash(),
+		},
+		Balances: []*types.Amount{{
+			Value: balance.String(),
+			Currency: &types.Currency{
+				Symbol:   c.cfg.Currency.Symbol,
+				Decimals: c.cfg.Currency.Decimals,
+				Metadata: nil,
+			}}},
+	}
+	return
+}
+
+func (c *grpcIoTexClient) getTokenBalance(ctx context.Context, contract, owner string) (*big.Int, error) {
+	data, err := xrc20BalanceOfData(owner)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.ReadContract(ctx, &iotexapi.ReadContractRequest{
+		Execution: &iotextypes.Execution{
+			Amount:   "0",
+			Contract: contract,
+			Data:     data,
+		},
+		CallerAddress: owner,
+		GasLimit:      readContractGasLimit,
+		GasPrice:      "0",
+	})
+	if err != nil {
+		return nil, err
+	}
+	raw, err := hex.DecodeString(resp.GetData())
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(raw), nil
+}
+
+func (c *grpcIoTexClient) GetTransactions(ctx context.Context, height int64) (ret []*types.Transaction, err error) {
+	ret = make([]*types.Transaction, 0)
+	if err = c.connect(); err != nil {
+		return
+	}
+	var (
+		actionMap      map[string]*iotextypes.Action
+		receiptMap     map[string]*iotextypes.Receipt
+		hashSlice      []string
+		transferLogMap map[string][]*iotextypes.TransactionLog_Transaction
+	)
+	blk, prefetched := c.prefetch.lookup(uint64(height))
+	if prefetched {
+		actionMap, receiptMap, hashSlice, err = parseRawBlock(blk.info, c.cfg.NetworkIdentifier.EvmNetworkID)
+	} else {
+		actionMap, receiptMap, hashSlice, err = c.getRawBlock(ctx, height)
+	}
 	if err != nil {
 		return
 	}
+	if prefetched && blk.withLogs {
+		transferLogMap = transactionLogMap(blk.info.GetTransactionLogs())
+	} else {
+		// get TransactionLog by height,if log is not exist,the err will be nil
+		transferLogMap, err = getTransactionLog(ctx, height, c.client)
+		if err != nil {
+			return
+		}
+	}
 	for _, h := range hashSlice {
+		var transaction *types.Transaction
 		if transferLogMap[h] != nil {
-			transaction := c.packTransaction(h, transferLogMap[h])
-			ret = append(ret, transaction)
+			transaction = c.packTransaction(h, transferLogMap[h], receiptMap[h])
 		} else if c.cfg.KeepNoneTxAction {
-			ret = append(ret, c.genNoneTxActTransaction(h, actionMap[h]))
+			transaction = c.genNoneTxActTransaction(h, actionMap[h])
+		}
+		receiptOps := c.receiptOperations(receiptMap[h])
+		if len(receiptOps) > 0 {
+			if transaction == nil {
+				transaction = &types.Transaction{TransactionIdentifier: &types.TransactionIdentifier{Hash: h}}
+			}
+			transaction.Operations = append(transaction.Operations, receiptOps...)
+		}
+		if transaction != nil {
+			ret = append(ret, transaction)
 		}
-
 	}
 	ret = fillIndex(ret)
+	if height == genesisHeight && c.genesisTx != nil {
+		ret = append([]*types.Transaction{c.genesisTx}, ret...)
+	}
 	return
 }
 
@@ -226,7 +415,7 @@ func (c *grpcIoTexClient) GetStatus(ctx context.Context) (*iotexapi.GetChainMeta
 	if err != nil {
 		return nil, err
 	}
-	return c.client.GetChainMeta(context.Background(), &iotexapi.GetChainMetaRequest{})
+	return c.client.GetChainMeta(ctx, &iotexapi.GetChainMetaRequest{})
 }
 
 func (c *grpcIoTexClient) GetVersion(ctx context.Context) (*iotexapi.GetServerMetaResponse, error) {
@@ -237,6 +426,22 @@ func (c *grpcIoTexClient) GetVersion(ctx context.Context) (*iotexapi.GetServerMe
 	return c.client.GetServerMeta(ctx, &iotexapi.GetServerMetaRequest{})
 }
 
+func (c *grpcIoTexClient) GetTargetHeight() (int64, error) {
+	if err := c.connect(); err != nil {
+		return 0, err
+	}
+	return int64(c.pool.tipHeight()), nil
+}
+
+func (c *grpcIoTexClient) Close() error {
+	c.Lock()
+	defer c.Unlock()
+	if c.pool != nil {
+		c.pool.Close()
+	}
+	return nil
+}
+
 func (c *grpcIoTexClient) GetConfig() *config.Config {
 	return c.cfg
 }
@@ -244,19 +449,21 @@ func (c *grpcIoTexClient) GetConfig() *config.Config {
 func (c *grpcIoTexClient) connect() (err error) {
 	c.Lock()
 	defer c.Unlock()
-	// Check if the existing connection is good.
-	if c.grpcConn != nil && c.grpcConn.GetState() != connectivity.Shutdown {
+	// The pool keeps reconnecting to the nodes by itself.
+	if c.pool != nil {
 		return
 	}
-	opts := []grpc.DialOption{}
-	if c.cfg.Server.SecureEndpoint {
-		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})))
-	} else {
-		opts = append(opts, grpc.WithInsecure())
+	if c.cfg.Server.Offline() {
+		return ErrOfflineMode
+	}
+	c.pool, err = newEndpointPool(c.cfg.Server)
+	if err != nil {
+		upstreamConnects.WithLabelValues("failure").Inc()
+		return
 	}
-	c.grpcConn, err = grpc.Dial(c.cfg.Server.Endpoint, opts...)
-	c.client = iotexapi.NewAPIServiceClient(c.grpcConn)
-	return err
+	upstreamConnects.WithLabelValues("success").Inc()
+	c.client = iotexapi.NewAPIServiceClient(c.pool)
+	return
 }
 
 func genBlock(parentBlk, blk *iotextypes.BlockMeta) *types.Block {
@@ -304,6 +511,34 @@ func (c *grpcIoTexClient) getBlock(ctx context.Context, height int64) (ret *type
 	return
 }
 
+func (c *grpcIoTexClient) getBlockByHash(ctx context.Context, hash string) (ret *types.Block, err error) {
+	request := &iotexapi.GetBlockMetasRequest{
+		Lookup: &iotexapi.GetBlockMetasRequest_ByHash{
+			ByHash: &iotexapi.GetBlockMetaByHashRequest{
+				BlkHash: hash,
+			},
+		},
+	}
+	resp, err := c.client.GetBlockMetas(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.BlkMetas) == 0 {
+		return nil, errors.New("not found")
+	}
+	blk := resp.BlkMetas[0]
+	// the genesis block is its own parent, same as getBlock
+	parentBlk := blk
+	if blk.Height > 1 {
+		parentBlk = &iotextypes.BlockMeta{
+			Hash:   blk.PreviousBlockHash,
+			Height: blk.Height - 1,
+		}
+	}
+	ret = genBlock(parentBlk, blk)
+	return
+}
+
 func (c *grpcIoTexClient) getRawBlock(ctx context.Context, height int64) (actionMap map[string]*iotextypes.Action, receiptMap map[string]*iotextypes.Receipt, hashSlice []string, err error) {
 	getRawBlocksRes, err := c.client.GetRawBlocks(ctx, &iotexapi.GetRawBlocksRequest{
 		StartHeight:  uint64(height),
@@ -313,21 +548,22 @@ func (c *grpcIoTexClient) getRawBlock(ctx context.Context, height int64) (action
 	if err != nil || len(getRawBlocksRes.GetBlocks()) != 1 {
 		return
 	}
+	return parseRawBlock(getRawBlocksRes.GetBlocks()[0], c.cfg.NetworkIdentifier.EvmNetworkID)
+}
 
+// parseRawBlock returns the actions and receipts of the block keyed by action
+// hash, along with the hashes in block order.
+func parseRawBlock(blk *iotexapi.BlockInfo, evmNetworkID uint32) (actionMap map[string]*iotextypes.Action, receiptMap map[string]*iotextypes.Receipt, hashSlice []string, err error) {
 	actionMap = make(map[string]*iotextypes.Action)
 	receiptMap = make(map[string]*iotextypes.Receipt)
 	// hashSlice for fixed sequence,b/c map is unordered
 	hashSlice = make([]string, 0)
-	blk := getRawBlocksRes.GetBlocks()[0]
 	for _, act := range blk.GetBlock().GetBody().GetActions() {
-		var selp action.SealedEnvelope
-		err = selp.LoadProto(act)
+		var h string
+		h, err = actionHash(act, evmNetworkID)
 		if err != nil {
 			return
 		}
-		hashArray, _ := selp.Hash()
-
-		h := hex.EncodeToString(hashArray[:])
 		actionMap[h] = act
 		hashSlice = append(hashSlice, h)
 	}
@@ -349,20 +585,53 @@ func (c *grpcIoTexClient) genNoneTxActTransaction(h string, act *iotextypes.Acti
 		Recipient: address.RewardingPoolAddr,
 		Amount:    "0",
 	}
-	return c.packTransaction(h, []*iotextypes.TransactionLog_Transaction{tx})
+	return c.packTransaction(h, []*iotextypes.TransactionLog_Transaction{tx}, nil)
 }
 
-func (c *grpcIoTexClient) packTransaction(h string, transferLogs []*iotextypes.TransactionLog_Transaction) *types.Transaction {
+func (c *grpcIoTexClient) packTransaction(h string, transferLogs []*iotextypes.TransactionLog_Transaction, receipt *iotextypes.Receipt) *types.Transaction {
 	ret := &types.Transaction{TransactionIdentifier: &types.TransactionIdentifier{h}}
 	ret.Operations = make([]*types.Operation, 0, len(transferLogs))
 	for _, t := range transferLogs {
-		ops := c.covertToOperations(t)
+		ops := c.covertToOperations(t, transactionLogStatus(t.GetType(), receipt))
+		if pool, account := poolSubAccount(t, receipt); account != nil {
+			for _, op := range ops {
+				if op.Account.Address == pool {
+					op.Account = account
+				}
+			}
+		}
 		ret.Operations = append(ret.Operations, ops...)
 	}
 	return ret
 }
 
-func (c *grpcIoTexClient) covertToOperations(s *iotextypes.TransactionLog_Transaction) []*types.Operation {
+// poolSubAccount returns the sub-account standing for the pool of the
+// transaction log, nil if the pool holds the funds on its own. The funds of a
+// bucket and the unclaimed rewards are held by sub-accounts of their owners
+// rather than by the bucket pool and the rewarding pool.
+func poolSubAccount(t *iotextypes.TransactionLog_Transaction, receipt *iotextypes.Receipt) (pool string, account *types.AccountIdentifier) {
+	if t.GetType() == iotextypes.TransactionLogType_CLAIM_FROM_REWARDING_FUND {
+		return address.RewardingPoolAddr, &types.AccountIdentifier{
+			Address: t.GetRecipient(),
+			SubAccount: &types.SubAccountIdentifier{
+				Address: RewardsSubAccount,
+			},
+		}
+	}
+	return address.StakingBucketPoolAddr, bucketAccount(t, receipt)
+}
+
+// transactionLogStatus returns the status of the operations of a transaction
+// log, the gas fee is charged even if the action failed.
+func transactionLogStatus(typ iotextypes.TransactionLogType, receipt *iotextypes.Receipt) string {
+	if receipt == nil || typ == iotextypes.TransactionLogType_GAS_FEE ||
+		receipt.GetStatus() == uint64(iotextypes.ReceiptStatus_Success) {
+		return StatusSuccess
+	}
+	return StatusFail
+}
+
+func (c *grpcIoTexClient) covertToOperations(s *iotextypes.TransactionLog_Transaction, status string) []*types.Operation {
 	ops := make([]*types.Operation, 0, 2)
 	// sender
 	if s.GetSender() != "" {
@@ -376,7 +645,7 @@ func (c *grpcIoTexClient) covertToOperations(s *iotextypes.TransactionLog_Transa
 			},
 			RelatedOperations: nil,
 			Type:              s.GetType().String(),
-			Status:            types.String(StatusSuccess),
+			Status:            types.String(status),
 			Account: &types.AccountIdentifier{
 				Address:    s.GetSender(),
 				SubAccount: nil,
@@ -403,7 +672,7 @@ func (c *grpcIoTexClient) covertToOperations(s *iotextypes.TransactionLog_Transa
 			},
 			RelatedOperations: nil,
 			Type:              s.GetType().String(),
-			Status:            types.String(StatusSuccess),
+			Status:            types.String(status),
 			Account: &types.AccountIdentifier{
 				Address:    s.GetRecipient(),
 				SubAccount: nil,
@@ -433,21 +702,97 @@ func (c *grpcIoTexClient) GetBlockTransaction(ctx context.Context, actionHash st
 }
 
 func (c *grpcIoTexClient) getBlockTransaction(ctx context.Context, actionHash string) (ret *types.Transaction, err error) {
+	if c.genesisTx != nil && actionHash == c.genesisTx.TransactionIdentifier.Hash {
+		return c.genesisTx, nil
+	}
 	request := &iotexapi.GetTransactionLogByActionHashRequest{
 		ActionHash: actionHash,
 	}
 
 	resp, err := c.client.GetTransactionLogByActionHash(ctx, request)
+	// grant reward actions have no transaction log
+	if err != nil && status.Code(err) != codes.NotFound {
+		return nil, err
+	}
+	receiptResp, err := c.client.GetReceiptByAction(ctx, &iotexapi.GetReceiptByActionRequest{ActionHash: actionHash})
 	if err != nil {
 		return nil, err
 	}
-	if resp.TransactionLog == nil {
-		return nil, errors.New("not found")
+	receipt := receiptResp.GetReceiptInfo().GetReceipt()
+	receiptOps := c.receiptOperations(receipt)
+	if resp.GetTransactionLog() == nil {
+		if len(receiptOps) == 0 {
+			return nil, errors.New("not found")
+		}
+		ret = &types.Transaction{TransactionIdentifier: &types.TransactionIdentifier{Hash: hex.EncodeToString(receipt.GetActHash())}}
+	} else {
+		ret = c.packTransaction(hex.EncodeToString(resp.TransactionLog.ActionHash), resp.TransactionLog.Transactions, receipt)
+	}
+	ret.Operations = append(ret.Operations, receiptOps...)
+	for i, oper := range ret.Operations {
+		oper.OperationIdentifier.Index = int64(i)
 	}
-	ret = c.packTransaction(hex.EncodeToString(resp.TransactionLog.ActionHash), resp.TransactionLog.Transactions)
 	return
 }
 
+// receiptOperations returns the operations decoded from the logs of the
+// receipt, which come after the ones of the transaction logs in both the
+// blocks and the single transactions.
+func (c *grpcIoTexClient) receiptOperations(receipt *iotextypes.Receipt) []*types.Operation {
+	return append(c.rewardOperations(receipt), c.xrc20TransferOperations(receipt)...)
+}
+
+// xrc20TransferOperations returns the operations of the configured XRC20
+// token transfers emitted in the receipt.
+func (c *grpcIoTexClient) xrc20TransferOperations(receipt *iotextypes.Receipt) []*types.Operation {
+	ops := make([]*types.Operation, 0)
+	for _, l := range receipt.GetLogs() {
+		token, ok := c.cfg.TokenByContract(l.GetContractAddress())
+		if !ok {
+			continue
+		}
+		from, to, amount, ok := parseXrc20TransferLog(l)
+		if !ok {
+			continue
+		}
+		currency := TokenCurrency(token)
+		// mint and burn only change the balance of one side
+		if from.String() != zeroAddress.String() {
+			senderAmount := "-" + amount.String()
+			if amount.Sign() == 0 {
+				senderAmount = amount.String()
+			}
+			ops = append(ops, c.genTokenOperation(from.String(), senderAmount, currency))
+		}
+		if to.String() != zeroAddress.String() {
+			ops = append(ops, c.genTokenOperation(to.String(), amount.String(), currency))
+		}
+	}
+	return ops
+}
+
+func (c *grpcIoTexClient) genTokenOperation(addr, amount string, currency *types.Currency) *types.Operation {
+	return &types.Operation{
+		OperationIdentifier: &types.OperationIdentifier{
+			NetworkIndex: nil,
+		},
+		RelatedOperations: nil,
+		Type:              Xrc20TransferType,
+		Status:            types.String(StatusSuccess),
+		Account: &types.AccountIdentifier{
+			Address:    addr,
+			SubAccount: nil,
+			Metadata:   nil,
+		},
+		Amount: &types.Amount{
+			Value:    amount,
+			Currency: currency,
+			Metadata: nil,
+		},
+		Metadata: nil,
+	}
+}
+
 func (c *grpcIoTexClient) GetMemPool(ctx context.Context, actionHashes []string) (ret []*types.TransactionIdentifier, err error) {
 	if err = c.connect(); err != nil {
 		return
@@ -465,13 +810,12 @@ func (c *grpcIoTexClient) getMemPool(ctx context.Context, actionHashes []string)
 		return nil, err
 	}
 	for _, act := range resp.Actions {
-		byteAct, err := proto.Marshal(act)
+		h, err := actionHash(act, c.cfg.NetworkIdentifier.EvmNetworkID)
 		if err != nil {
 			return nil, err
 		}
-		h := hash.Hash256b(byteAct)
 		ret = append(ret, &types.TransactionIdentifier{
-			Hash: hex.EncodeToString(h[:]),
+			Hash: h,
 		})
 	}
 
@@ -4,17 +4,23 @@ import (
 	"context"
 	"encoding/hex"
 	"errors"
+	"math/big"
 	"math/rand"
 	"net"
+	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
 	"time"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
 	"github.com/iotexproject/iotex-core/action"
+	"github.com/iotexproject/iotex-core/action/protocol/rewarding/rewardingpb"
 	"github.com/iotexproject/iotex-core/blockchain/block"
 	"github.com/iotexproject/iotex-core/pkg/unit"
 	"github.com/iotexproject/iotex-core/test/identityset"
@@ -22,10 +28,16 @@ import (
 	"github.com/iotexproject/iotex-proto/golang/iotexapi"
 	"github.com/iotexproject/iotex-proto/golang/iotexapi/mock_iotexapi"
 	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	pkgerrors "github.com/pkg/errors"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
 
 	"github.com/iotexproject/iotex-core-rosetta-gateway/config"
+	"github.com/iotexproject/iotex-core-rosetta-gateway/iotex-client/mock_client"
 )
 
 func testServerAddr() string { return "127.0.0.1:14014" }
@@ -58,17 +70,20 @@ func testChain() []*iotextypes.BlockMeta {
 			Height:    1,
 			Timestamp: &timestamp.Timestamp{Seconds: now.Unix()},
 		}, {
-			Hash:      "hash 2",
-			Height:    2,
-			Timestamp: &timestamp.Timestamp{Seconds: now.Unix()},
+			Hash:              "hash 2",
+			Height:            2,
+			Timestamp:         &timestamp.Timestamp{Seconds: now.Unix()},
+			PreviousBlockHash: "genesis hash",
 		}, {
-			Hash:      "hash 3",
-			Height:    3,
-			Timestamp: &timestamp.Timestamp{Seconds: now.Unix()},
+			Hash:              "hash 3",
+			Height:            3,
+			Timestamp:         &timestamp.Timestamp{Seconds: now.Unix()},
+			PreviousBlockHash: "hash 2",
 		}, {
-			Hash:      "hash 4",
-			Height:    4,
-			Timestamp: &timestamp.Timestamp{Seconds: now.Unix()},
+			Hash:              "hash 4",
+			Height:            4,
+			Timestamp:         &timestamp.Timestamp{Seconds: now.Unix()},
+			PreviousBlockHash: "hash 3",
 		},
 	}
 }
@@ -160,9 +175,57 @@ func testTransactionLog() *iotextypes.TransactionLog {
 	}
 }
 
+func testGrantReward() action.SealedEnvelope {
+	gb := action.GrantRewardBuilder{}
+	grant := gb.SetRewardType(action.EpochReward).SetHeight(1).Build()
+	eb := action.EnvelopeBuilder{}
+	elp := eb.SetNonce(0).SetGasPrice(big.NewInt(0)).SetAction(&grant).Build()
+	selp, _ := action.Sign(elp, identityset.PrivateKey(29))
+	return selp
+}
+
+// testRewardLogs returns the reward logs of the grant reward action, the
+// operations expected from them are listed in the same order.
+func testRewardLogs(actHash hash.Hash256) ([]*action.Log, []*types.Operation) {
+	rewards := []*rewardingpb.RewardLog{
+		{Type: rewardingpb.RewardLog_BLOCK_REWARD, Addr: identityset.Address(1).String(), Amount: "16"},
+		{Type: rewardingpb.RewardLog_EPOCH_REWARD, Addr: identityset.Address(2).String(), Amount: "100"},
+		{Type: rewardingpb.RewardLog_FOUNDATION_BONUS, Addr: identityset.Address(2).String(), Amount: "80"},
+	}
+	logs := make([]*action.Log, 0, len(rewards))
+	ops := make([]*types.Operation, 0, len(rewards))
+	for i, reward := range rewards {
+		data, _ := proto.Marshal(reward)
+		logs = append(logs, &action.Log{
+			Address:     rewardingProtocolAddr,
+			Data:        data,
+			BlockHeight: 1,
+			ActionHash:  actHash,
+			Index:       uint32(i),
+		})
+		ops = append(ops, &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{Index: int64(i)},
+			Type:                reward.GetType().String(),
+			Status:              types.String(StatusSuccess),
+			Account: &types.AccountIdentifier{
+				Address:    reward.GetAddr(),
+				SubAccount: &types.SubAccountIdentifier{Address: RewardsSubAccount},
+			},
+			Amount: &types.Amount{
+				Value: reward.GetAmount(),
+				Currency: &types.Currency{
+					Symbol:   testConfig().Currency.Symbol,
+					Decimals: testConfig().Currency.Decimals,
+				},
+			},
+		})
+	}
+	return logs, ops
+}
+
 func testActions() []*iotextypes.Action {
 	senderPubKey, _ := hex.DecodeString("04403d3c0dbd3270ddfc248c3df1f9aafd60f1d8e7456961c9ef26292262cc68f0ea9690263bef9e197a38f06026814fc70912c2b98d2e90a68f8ddc5328180a01")
-	signature, _ := hex.DecodeString("010203040506070809")
+	signature := action.ValidSig
 	return []*iotextypes.Action{
 		{
 			Core:         &iotextypes.ActionCore{
@@ -211,6 +274,17 @@ func newMockServer(t *testing.T) (svr iotexapi.APIServiceServer, cli IoTexClient
 	service.EXPECT().
 		GetBlockMetas(gomock.Any(), gomock.AssignableToTypeOf(&iotexapi.GetBlockMetasRequest{})).
 		DoAndReturn(func(ctx context.Context, req *iotexapi.GetBlockMetasRequest) (*iotexapi.GetBlockMetasResponse, error) {
+			if byHash := req.GetByHash(); byHash != nil {
+				for _, blk := range chain {
+					if blk.Hash == byHash.BlkHash {
+						return &iotexapi.GetBlockMetasResponse{
+							Total:    1,
+							BlkMetas: []*iotextypes.BlockMeta{blk},
+						}, nil
+					}
+				}
+				return &iotexapi.GetBlockMetasResponse{}, nil
+			}
 			query := req.GetByIndex()
 			if query == nil {
 				return nil, errors.New("unsupported query method")
@@ -275,11 +349,21 @@ func newMockServer(t *testing.T) (svr iotexapi.APIServiceServer, cli IoTexClient
 		ContractAddress: "test",
 	}
 	receipt.AddLogs(testLog)
-	ra := block.NewRunnableActionsBuilder().Build()
+	grant := testGrantReward()
+	grantHash, err := grant.Hash()
+	require.NoError(err)
+	rewardLogs, _ := testRewardLogs(grantHash)
+	grantReceipt := &action.Receipt{
+		Status:      uint64(iotextypes.ReceiptStatus_Success),
+		BlockHeight: 1,
+		ActionHash:  grantHash,
+	}
+	grantReceipt.AddLogs(rewardLogs...)
+	ra := block.NewRunnableActionsBuilder().AddActions(grant).Build()
 	blk, err := block.NewBuilder(ra).
 		SetHeight(1).
 		SetTimestamp(testutil.TimestampNow()).
-		SetReceipts([]*action.Receipt{receipt}).
+		SetReceipts([]*action.Receipt{receipt, grantReceipt}).
 		SetPrevBlockHash(hash.ZeroHash256).
 		SignAndBuild(identityset.PrivateKey(29))
 	require.NoError(err)
@@ -300,11 +384,34 @@ func newMockServer(t *testing.T) (svr iotexapi.APIServiceServer, cli IoTexClient
 		AnyTimes()
 	service.EXPECT().
 		GetTransactionLogByActionHash(gomock.Any(), gomock.AssignableToTypeOf(&iotexapi.GetTransactionLogByActionHashRequest{})).
-		Return(&iotexapi.GetTransactionLogByActionHashResponse{
-			TransactionLog: transactionLog,
-		}, nil).
+		DoAndReturn(func(ctx context.Context, req *iotexapi.GetTransactionLogByActionHashRequest) (*iotexapi.GetTransactionLogByActionHashResponse, error) {
+			if req.ActionHash == hex.EncodeToString(grantHash[:]) {
+				return nil, status.Error(codes.NotFound, "transaction log not found")
+			}
+			return &iotexapi.GetTransactionLogByActionHashResponse{
+				TransactionLog: transactionLog,
+			}, nil
+		}).
 		AnyTimes()
-	t.Cleanup(server.Stop)
+	service.EXPECT().
+		GetReceiptByAction(gomock.Any(), gomock.AssignableToTypeOf(&iotexapi.GetReceiptByActionRequest{})).
+		DoAndReturn(func(ctx context.Context, req *iotexapi.GetReceiptByActionRequest) (*iotexapi.GetReceiptByActionResponse, error) {
+			if req.ActionHash == hex.EncodeToString(grantHash[:]) {
+				return &iotexapi.GetReceiptByActionResponse{
+					ReceiptInfo: &iotexapi.ReceiptInfo{Receipt: grantReceipt.ConvertToReceiptPb()},
+				}, nil
+			}
+			return &iotexapi.GetReceiptByActionResponse{
+				ReceiptInfo: &iotexapi.ReceiptInfo{
+					Receipt: &iotextypes.Receipt{Status: uint64(iotextypes.ReceiptStatus_Success)},
+				},
+			}, nil
+		}).
+		AnyTimes()
+	t.Cleanup(func() {
+		server.Stop()
+		listener.Close()
+	})
 	return service, cli
 }
 
@@ -343,6 +450,24 @@ func TestIoTexClient_GetBlock(t *testing.T) {
 	require.Equal(expectBlk, block)
 }
 
+func TestGrpcIoTexClient_GetBlockByHash(t *testing.T) {
+	var (
+		require = require.New(t)
+		chain   = testChain()
+	)
+	_, cli := newMockServer(t)
+	block, err := cli.GetBlockByHash(context.Background(), chain[2].Hash)
+	require.NoError(err)
+	require.Equal(genBlock(chain[1], chain[2]), block)
+
+	block, err = cli.GetBlockByHash(context.Background(), chain[0].Hash)
+	require.NoError(err)
+	require.Equal(genBlock(chain[0], chain[0]), block)
+
+	_, err = cli.GetBlockByHash(context.Background(), "unknown hash")
+	require.Error(err)
+}
+
 func TestGrpcIoTexClient_GetLatestBlock(t *testing.T) {
 	var (
 		require    = require.New(t)
@@ -372,9 +497,16 @@ func TestGrpcIoTexClient_GetGenesisBlock(t *testing.T) {
 
 func TestGrpcIoTexClient_GetAccount(t *testing.T) {
 	require := require.New(t)
-	_, cli := newMockServer(t)
-	_, err := cli.GetAccount(context.Background(), 0, "")
+	svr, cli := newMockServer(t)
+	_, err := cli.GetAccount(context.Background(), 0, "", nil)
+	require.NoError(err)
+
+	expect, err := svr.GetAccount(context.Background(), &iotexapi.GetAccountRequest{})
 	require.NoError(err)
+	tip := int64(expect.GetBlockIdentifier().GetHeight())
+	acc, err := cli.GetAccount(context.Background(), tip+1, "", nil)
+	require.Equal(ErrHistoricalStateUnavailable, err)
+	require.Nil(acc)
 }
 
 func TestGrpcIoTexClient_SubmitTx(t *testing.T) {
@@ -419,10 +551,33 @@ func TestGrpcIoTexClient_GetVersion(t *testing.T) {
 func TestGrpcIoTexClient_GetTransactions(t *testing.T) {
 	require := require.New(t)
 	_, cli := newMockServer(t)
-	// TODO: fill transactions data into mock server, and check it.
+	grant := testGrantReward()
+	grantHash, err := grant.Hash()
+	require.NoError(err)
+	_, rewardOps := testRewardLogs(grantHash)
 	transactions, err := cli.GetTransactions(context.Background(), 2)
 	require.NoError(err)
-	require.Equal([]*types.Transaction{}, transactions)
+	require.Equal([]*types.Transaction{{
+		TransactionIdentifier: &types.TransactionIdentifier{Hash: hex.EncodeToString(grantHash[:])},
+		Operations:            rewardOps,
+	}}, transactions)
+
+	// the rewards follow the empty gas fee of the action, the transactions
+	// assembled with the former config are cached
+	cli.GetConfig().KeepNoneTxAction = true
+	transactions, err = cli.(*cachedIoTexClient).IoTexClient.GetTransactions(context.Background(), 2)
+	require.NoError(err)
+	require.Len(transactions, 1)
+	ops := transactions[0].Operations
+	require.Len(ops, 2+len(rewardOps))
+	require.Equal(iotextypes.TransactionLogType_GAS_FEE.String(), ops[0].Type)
+	require.Equal("0", ops[0].Amount.Value)
+	for i, op := range rewardOps {
+		require.Equal(op.Type, ops[2+i].Type, "index: %d", i)
+		require.Equal(op.Account, ops[2+i].Account, "index: %d", i)
+		require.Equal(op.Amount, ops[2+i].Amount, "index: %d", i)
+		require.Equal(int64(2+i), ops[2+i].OperationIdentifier.Index, "index: %d", i)
+	}
 }
 
 func TestGrpcIoTexClient_GetConfig(t *testing.T) {
@@ -431,6 +586,19 @@ func TestGrpcIoTexClient_GetConfig(t *testing.T) {
 	require.Equal(t, testConfig(), config)
 }
 
+func TestGrpcIoTexClient_OfflineMode(t *testing.T) {
+	require := require.New(t)
+	cfg := testConfig()
+	cfg.Server.Mode = config.OfflineMode
+	cli, err := NewIoTexClient(cfg)
+	require.NoError(err)
+	_, err = cli.GetLatestBlock(context.Background())
+	require.Equal(ErrOfflineMode, pkgerrors.Cause(err))
+	_, err = cli.GetVersion(context.Background())
+	require.Equal(ErrOfflineMode, pkgerrors.Cause(err))
+	require.Nil(cli.(*cachedIoTexClient).IoTexClient.(*grpcIoTexClient).pool)
+}
+
 func TestGrpcIoTexClient_GetBlockTransaction(t *testing.T) {
 	require := require.New(t)
 	_, cli := newMockServer(t)
@@ -441,6 +609,62 @@ func TestGrpcIoTexClient_GetBlockTransaction(t *testing.T) {
 	require.Equal(tx.Transactions[0].Type.String(), transaction.Operations[0].Type)
 	require.Equal(tx.Transactions[0].Amount, transaction.Operations[0].Amount.Value)
 	require.Equal(tx.Transactions[0].Sender, transaction.Operations[0].Account.Address)
+	require.Equal(StatusSuccess, *transaction.Operations[0].Status)
+
+	grant := testGrantReward()
+	grantHash, err := grant.Hash()
+	require.NoError(err)
+	_, rewardOps := testRewardLogs(grantHash)
+	transaction, err = cli.GetBlockTransaction(context.Background(), hex.EncodeToString(grantHash[:]))
+	require.NoError(err)
+	require.Equal(&types.Transaction{
+		TransactionIdentifier: &types.TransactionIdentifier{Hash: hex.EncodeToString(grantHash[:])},
+		Operations:            rewardOps,
+	}, transaction)
+}
+
+func TestGrpcIoTexClient_PackTransactionStatus(t *testing.T) {
+	var (
+		require = require.New(t)
+		cli     = &grpcIoTexClient{cfg: testConfig()}
+		sender  = identityset.Address(1).String()
+		logs    = []*iotextypes.TransactionLog_Transaction{
+			{
+				Type:      iotextypes.TransactionLogType_GAS_FEE,
+				Amount:    "10",
+				Sender:    sender,
+				Recipient: address.RewardingPoolAddr,
+			}, {
+				Type:      iotextypes.TransactionLogType_IN_CONTRACT_TRANSFER,
+				Amount:    "100",
+				Sender:    sender,
+				Recipient: identityset.Address(2).String(),
+			},
+		}
+		tests = []struct {
+			receipt *iotextypes.Receipt
+			expect  []string
+		}{
+			{nil, []string{StatusSuccess, StatusSuccess, StatusSuccess, StatusSuccess}},
+			{
+				&iotextypes.Receipt{Status: uint64(iotextypes.ReceiptStatus_Success)},
+				[]string{StatusSuccess, StatusSuccess, StatusSuccess, StatusSuccess},
+			}, {
+				&iotextypes.Receipt{Status: uint64(iotextypes.ReceiptStatus_ErrExecutionReverted)},
+				[]string{StatusSuccess, StatusSuccess, StatusFail, StatusFail},
+			}, {
+				&iotextypes.Receipt{Status: uint64(iotextypes.ReceiptStatus_Failure)},
+				[]string{StatusSuccess, StatusSuccess, StatusFail, StatusFail},
+			},
+		}
+	)
+	for i, test := range tests {
+		tx := cli.packTransaction("hash", logs, test.receipt)
+		require.Len(tx.Operations, len(test.expect), "index: %d", i)
+		for j, op := range tx.Operations {
+			require.Equal(test.expect[j], *op.Status, "index: %d", i)
+		}
+	}
 }
 
 func TestGrpcIoTexClient_GetMemPool(t *testing.T) {
@@ -449,6 +673,11 @@ func TestGrpcIoTexClient_GetMemPool(t *testing.T) {
 	acts, err := cli.GetMemPool(context.Background(), []string{})
 	require.NoError(err)
 	require.Equal(true, len(acts) > 0)
+	var selp action.SealedEnvelope
+	require.NoError(selp.LoadProto(testActions()[0]))
+	h, err := selp.Hash()
+	require.NoError(err)
+	require.Equal(hex.EncodeToString(h[:]), acts[0].Hash)
 
 	acts, err = cli.GetMemPool(context.Background(), []string{"322884fb04663019be6fb461d9453827487eafdd57b4de3bd89a7d77c9bf8395"})
 	require.NoError(err)
@@ -472,3 +701,865 @@ func TestGrpcIoTexClient_GetMemPoolTransaction(t *testing.T) {
 		}
 	}
 }
+
+func TestXrc20TransferData(t *testing.T) {
+	require := require.New(t)
+	recipient := identityset.Address(1).String()
+	amount, _ := new(big.Int).SetString("1000000000000000000000", 10)
+	data, err := Xrc20TransferData(recipient, amount)
+	require.NoError(err)
+	require.Equal("a9059cbb", hex.EncodeToString(data[:4]))
+	to, value, ok := ParseXrc20TransferData(data)
+	require.True(ok)
+	require.Equal(recipient, to)
+	require.Equal(amount, value)
+
+	_, _, ok = ParseXrc20TransferData(data[:len(data)-1])
+	require.False(ok)
+	_, err = Xrc20TransferData(recipient, big.NewInt(-1))
+	require.Error(err)
+}
+
+func testXrc20TransferLog(contract string, from, to []byte, amount int64) *iotextypes.Log {
+	return &iotextypes.Log{
+		ContractAddress: contract,
+		Topics:          [][]byte{xrc20TransferTopic, abiWord(from), abiWord(to)},
+		Data:            abiWord(big.NewInt(amount).Bytes()),
+	}
+}
+
+func TestGrpcIoTexClient_Xrc20TransferOperations(t *testing.T) {
+	var (
+		require  = require.New(t)
+		cfg      = testConfig()
+		contract = identityset.Address(10).String()
+		sender   = identityset.Address(1)
+		receiver = identityset.Address(2)
+	)
+	cfg.Tokens = []config.Token{{Contract: contract, Symbol: "VITA", Decimals: 18}}
+	cli := &grpcIoTexClient{cfg: cfg}
+	currency := TokenCurrency(cfg.Tokens[0])
+	ops := cli.xrc20TransferOperations(&iotextypes.Receipt{
+		Logs: []*iotextypes.Log{
+			testXrc20TransferLog(contract, sender.Bytes(), receiver.Bytes(), 10),
+			// mint
+			testXrc20TransferLog(contract, make([]byte, 20), receiver.Bytes(), 5),
+			// unknown token
+			testXrc20TransferLog(identityset.Address(11).String(), sender.Bytes(), receiver.Bytes(), 1),
+			// not a transfer
+			{ContractAddress: contract, Topics: [][]byte{hash.ZeroHash256[:]}},
+		},
+	})
+	require.Len(ops, 3)
+	expect := []struct {
+		addr   string
+		amount string
+	}{
+		{sender.String(), "-10"},
+		{receiver.String(), "10"},
+		{receiver.String(), "5"},
+	}
+	for i, e := range expect {
+		require.Equal(Xrc20TransferType, ops[i].Type)
+		require.Equal(e.addr, ops[i].Account.Address)
+		require.Equal(e.amount, ops[i].Amount.Value)
+		require.Equal(currency, ops[i].Amount.Currency)
+	}
+}
+
+func TestGrpcIoTexClient_GetAccountTokenBalance(t *testing.T) {
+	var (
+		require  = require.New(t)
+		contract = identityset.Address(10).String()
+		owner    = identityset.Address(1).String()
+	)
+	svr, cli := newMockServer(t)
+	token := config.Token{Contract: contract, Symbol: "VITA", Decimals: 18}
+	cli.GetConfig().Tokens = []config.Token{token}
+	svr.(*mock_iotexapi.MockAPIServiceServer).EXPECT().
+		ReadContract(gomock.Any(), gomock.AssignableToTypeOf(&iotexapi.ReadContractRequest{})).
+		DoAndReturn(func(ctx context.Context, req *iotexapi.ReadContractRequest) (*iotexapi.ReadContractResponse, error) {
+			require.Equal(contract, req.GetExecution().GetContract())
+			require.Equal("70a08231", hex.EncodeToString(req.GetExecution().GetData()[:4]))
+			return &iotexapi.ReadContractResponse{
+				Data: hex.EncodeToString(abiWord(big.NewInt(42).Bytes())),
+			}, nil
+		}).
+		Times(1)
+	resp, err := cli.GetAccount(context.Background(), 0, owner, nil)
+	require.NoError(err)
+	require.Len(resp.Balances, 2)
+	require.Equal("42", resp.Balances[1].Value)
+	require.Equal(TokenCurrency(token), resp.Balances[1].Currency)
+
+	// the token is not read unless it is requested
+	resp, err = cli.GetAccount(context.Background(), 0, owner, []*types.Currency{resp.Balances[0].Currency})
+	require.NoError(err)
+	require.Len(resp.Balances, 1)
+
+	// a failing token is left out unless it is requested
+	svr.(*mock_iotexapi.MockAPIServiceServer).EXPECT().
+		ReadContract(gomock.Any(), gomock.AssignableToTypeOf(&iotexapi.ReadContractRequest{})).
+		Return(nil, status.Error(codes.Internal, "execution reverted")).
+		Times(2)
+	resp, err = cli.GetAccount(context.Background(), 0, owner, nil)
+	require.NoError(err)
+	require.Len(resp.Balances, 1)
+	_, err = cli.GetAccount(context.Background(), 0, owner, []*types.Currency{TokenCurrency(token)})
+	require.Equal(codes.Internal, status.Code(err))
+}
+
+func TestGrpcIoTexClient_GetSubAccount(t *testing.T) {
+	var (
+		require = require.New(t)
+		owner   = identityset.Address(1).String()
+		start   = &timestamp.Timestamp{Seconds: 1600000000}
+		epoch   = &timestamp.Timestamp{}
+		tip     = &iotextypes.BlockIdentifier{Hash: "hash 3", Height: 3}
+		buckets = []*iotextypes.VoteBucket{
+			{Index: 1, StakedAmount: "100", StakeStartTime: start, UnstakeStartTime: epoch, Owner: owner},
+			{Index: 2, StakedAmount: "50", StakeStartTime: start, UnstakeStartTime: &timestamp.Timestamp{Seconds: 1600003600}, Owner: owner},
+			{Index: 3, StakedAmount: "20", StakeStartTime: start, UnstakeStartTime: epoch, Owner: owner},
+		}
+	)
+	svr, cli := newMockServer(t)
+	svr.(*mock_iotexapi.MockAPIServiceServer).EXPECT().
+		ReadState(gomock.Any(), gomock.AssignableToTypeOf(&iotexapi.ReadStateRequest{})).
+		DoAndReturn(func(ctx context.Context, req *iotexapi.ReadStateRequest) (*iotexapi.ReadStateResponse, error) {
+			if string(req.GetProtocolID()) == "rewarding" {
+				require.Equal("UnclaimedBalance", string(req.GetMethodName()))
+				require.Equal([][]byte{[]byte(owner)}, req.GetArguments())
+				return &iotexapi.ReadStateResponse{Data: []byte("42"), BlockIdentifier: tip}, nil
+			}
+			require.Equal("staking", string(req.GetProtocolID()))
+			method := &iotexapi.ReadStakingDataMethod{}
+			require.NoError(proto.Unmarshal(req.GetMethodName(), method))
+			require.Equal(iotexapi.ReadStakingDataMethod_BUCKETS_BY_VOTER, method.GetMethod())
+			arg := &iotexapi.ReadStakingDataRequest{}
+			require.NoError(proto.Unmarshal(req.GetArguments()[0], arg))
+			require.Equal(owner, arg.GetBucketsByVoter().GetVoterAddress())
+			data, err := proto.Marshal(&iotextypes.VoteBucketList{Buckets: buckets})
+			require.NoError(err)
+			return &iotexapi.ReadStateResponse{Data: data, BlockIdentifier: tip}, nil
+		}).
+		AnyTimes()
+
+	tests := []struct {
+		subAccount string
+		expect     string
+	}{
+		{RewardsSubAccount, "42"},
+		{BucketSubAccount(1), "100"},
+		{BucketSubAccount(2), "50"},
+		{BucketSubAccount(4), "0"},
+	}
+	for i, test := range tests {
+		resp, err := cli.GetSubAccount(context.Background(), 0, owner, test.subAccount)
+		require.NoError(err, "index: %d", i)
+		require.EqualValues(tip.GetHeight(), resp.BlockIdentifier.Index, "index: %d", i)
+		require.Len(resp.Balances, 1, "index: %d", i)
+		require.Equal(test.expect, resp.Balances[0].Value, "index: %d", i)
+	}
+
+	for _, subAccount := range []string{"bucket:x", "staked", "unstaking"} {
+		_, err := cli.GetSubAccount(context.Background(), 0, owner, subAccount)
+		require.Equal(ErrUnknownSubAccount, pkgerrors.Cause(err), subAccount)
+	}
+	_, err := cli.GetSubAccount(context.Background(), int64(tip.GetHeight())-1, owner, BucketSubAccount(1))
+	require.Equal(ErrHistoricalStateUnavailable, err)
+}
+
+func TestGrpcIoTexClient_PoolSubAccounts(t *testing.T) {
+	var (
+		require = require.New(t)
+		cli     = &grpcIoTexClient{cfg: testConfig()}
+		caller  = identityset.Address(1)
+		owner   = identityset.Address(2)
+		receipt = func(index uint64, addr address.Address) *iotextypes.Receipt {
+			idx := hash.BytesToHash256(big.NewInt(int64(index)).Bytes())
+			other := hash.BytesToHash256(addr.Bytes())
+			return &iotextypes.Receipt{
+				Status: uint64(iotextypes.ReceiptStatus_Success),
+				Logs: []*iotextypes.Log{{
+					ContractAddress: stakingProtocolAddr,
+					Topics:          [][]byte{hash.ZeroHash256[:], idx[:], other[:]},
+				}},
+			}
+		}
+		tests = []struct {
+			typ       iotextypes.TransactionLogType
+			sender    string
+			recipient string
+			receipt   *iotextypes.Receipt
+			expect    []*types.AccountIdentifier
+		}{
+			{
+				iotextypes.TransactionLogType_CREATE_BUCKET, caller.String(), address.StakingBucketPoolAddr,
+				receipt(7, identityset.Address(3)),
+				[]*types.AccountIdentifier{
+					{Address: caller.String()},
+					{Address: caller.String(), SubAccount: &types.SubAccountIdentifier{Address: "bucket:7"}},
+				},
+			}, {
+				iotextypes.TransactionLogType_DEPOSIT_TO_BUCKET, caller.String(), address.StakingBucketPoolAddr,
+				receipt(7, owner),
+				[]*types.AccountIdentifier{
+					{Address: caller.String()},
+					{Address: owner.String(), SubAccount: &types.SubAccountIdentifier{Address: "bucket:7"}},
+				},
+			}, {
+				iotextypes.TransactionLogType_CANDIDATE_SELF_STAKE, caller.String(), address.StakingBucketPoolAddr,
+				receipt(8, owner),
+				[]*types.AccountIdentifier{
+					{Address: caller.String()},
+					{Address: owner.String(), SubAccount: &types.SubAccountIdentifier{Address: "bucket:8"}},
+				},
+			}, {
+				iotextypes.TransactionLogType_WITHDRAW_BUCKET, address.StakingBucketPoolAddr, caller.String(),
+				receipt(7, identityset.Address(3)),
+				[]*types.AccountIdentifier{
+					{Address: caller.String(), SubAccount: &types.SubAccountIdentifier{Address: "bucket:7"}},
+					{Address: caller.String()},
+				},
+			}, {
+				iotextypes.TransactionLogType_CLAIM_FROM_REWARDING_FUND, address.RewardingPoolAddr, caller.String(),
+				nil,
+				[]*types.AccountIdentifier{
+					{Address: caller.String(), SubAccount: &types.SubAccountIdentifier{Address: RewardsSubAccount}},
+					{Address: caller.String()},
+				},
+			}, {
+				// the bucket pool is kept without the staking log
+				iotextypes.TransactionLogType_CREATE_BUCKET, caller.String(), address.StakingBucketPoolAddr,
+				nil,
+				[]*types.AccountIdentifier{
+					{Address: caller.String()},
+					{Address: address.StakingBucketPoolAddr},
+				},
+			},
+		}
+	)
+	for i, test := range tests {
+		tx := cli.packTransaction("hash", []*iotextypes.TransactionLog_Transaction{{
+			Type:      test.typ,
+			Amount:    "100",
+			Sender:    test.sender,
+			Recipient: test.recipient,
+		}}, test.receipt)
+		require.Len(tx.Operations, len(test.expect), "index: %d", i)
+		for j, op := range tx.Operations {
+			require.Equal(test.expect[j], op.Account, "index: %d", i)
+		}
+	}
+}
+
+func TestGrpcIoTexClient_GenesisAllocations(t *testing.T) {
+	require := require.New(t)
+	_, _ = newMockServer(t)
+	genesisPath := filepath.Join(t.TempDir(), "genesis.yaml")
+	require.NoError(os.WriteFile(genesisPath, []byte(`account:
+  initBalances:
+    io1vdtfpzkwpyngzvx7u2mauepnzja7kd5rryp0sg: "7000000000000000000000000000"
+    io1mflp9m6hcgm2qcghchsdqj3z3eccrnekx9p0ms: "1000000000000000000000000000"
+`), 0644))
+	cfg := testConfig()
+	cfg.GenesisPath = genesisPath
+	cli, err := NewIoTexClient(cfg)
+	require.NoError(err)
+
+	transactions, err := cli.GetTransactions(context.Background(), genesisHeight)
+	require.NoError(err)
+	require.True(len(transactions) > 0)
+	genesisTx := transactions[0]
+	expect := []struct {
+		addr   string
+		amount string
+	}{
+		{"io1mflp9m6hcgm2qcghchsdqj3z3eccrnekx9p0ms", "1000000000000000000000000000"},
+		{"io1vdtfpzkwpyngzvx7u2mauepnzja7kd5rryp0sg", "7000000000000000000000000000"},
+	}
+	require.Len(genesisTx.Operations, len(expect))
+	for i, e := range expect {
+		op := genesisTx.Operations[i]
+		require.Equal(int64(i), op.OperationIdentifier.Index, "index: %d", i)
+		require.Equal(GenesisAllocationType, op.Type, "index: %d", i)
+		require.Equal(StatusSuccess, *op.Status, "index: %d", i)
+		require.Equal(e.addr, op.Account.Address, "index: %d", i)
+		require.Equal(e.amount, op.Amount.Value, "index: %d", i)
+	}
+
+	transaction, err := cli.GetBlockTransaction(context.Background(), genesisTx.TransactionIdentifier.Hash)
+	require.NoError(err)
+	require.Equal(genesisTx, transaction)
+
+	// the allocations only belong to the genesis block
+	transactions, err = cli.GetTransactions(context.Background(), genesisHeight+1)
+	require.NoError(err)
+	for _, tx := range transactions {
+		require.NotEqual(genesisTx.TransactionIdentifier.Hash, tx.TransactionIdentifier.Hash)
+	}
+
+	cfg.GenesisPath = filepath.Join(t.TempDir(), "missing.yaml")
+	_, err = NewIoTexClient(cfg)
+	require.Error(err)
+}
+
+func newChainMetaServer(t *testing.T, addr string, height uint64) *grpc.Server {
+	require := require.New(t)
+	service := mock_iotexapi.NewMockAPIServiceServer(gomock.NewController(t))
+	service.EXPECT().
+		GetChainMeta(gomock.Any(), gomock.Any()).
+		Return(&iotexapi.GetChainMetaResponse{ChainMeta: &iotextypes.ChainMeta{Height: height}}, nil).
+		AnyTimes()
+	service.EXPECT().
+		GetServerMeta(gomock.Any(), gomock.Any()).
+		Return(&iotexapi.GetServerMetaResponse{ServerMeta: &iotextypes.ServerMeta{PackageVersion: addr}}, nil).
+		AnyTimes()
+	server := grpc.NewServer()
+	iotexapi.RegisterAPIServiceServer(server, service)
+	listener, err := net.Listen("tcp", addr)
+	require.NoError(err)
+	go server.Serve(listener)
+	t.Cleanup(func() {
+		server.Stop()
+		listener.Close()
+	})
+	return server
+}
+
+func TestEndpointPool_Candidates(t *testing.T) {
+	require := require.New(t)
+	tests := []struct {
+		endpoints []*endpoint
+		expect    []string
+		// the tip of the healthy nodes
+		tip uint64
+	}{
+		{
+			[]*endpoint{
+				{addr: "a", height: 100, healthy: true},
+				{addr: "b", height: 103, healthy: true},
+				{addr: "c", height: 200, healthy: false},
+			},
+			[]string{"b", "a", "c"},
+			103,
+		}, {
+			// a lags behind more than the max height lag
+			[]*endpoint{
+				{addr: "a", height: 90, healthy: true},
+				{addr: "b", height: 100, healthy: true},
+				{addr: "c", height: 97, healthy: true},
+			},
+			[]string{"b", "c", "a"},
+			100,
+		}, {
+			[]*endpoint{
+				{addr: "a", healthy: false},
+				{addr: "b", healthy: false},
+			},
+			[]string{"a", "b"},
+			0,
+		},
+	}
+	for i, test := range tests {
+		p := &endpointPool{endpoints: test.endpoints, maxHeightLag: 5}
+		addrs := []string{}
+		for _, e := range p.candidates() {
+			addrs = append(addrs, e.addr)
+		}
+		require.Equal(test.expect, addrs, "index: %d", i)
+		require.Equal(test.tip, p.tipHeight(), "index: %d", i)
+	}
+}
+
+func TestEndpointPool_Failover(t *testing.T) {
+	var (
+		require = require.New(t)
+		behind  = "127.0.0.1:14015"
+		ahead   = "127.0.0.1:14016"
+	)
+	newChainMetaServer(t, behind, 100)
+	server := newChainMetaServer(t, ahead, 110)
+	p, err := newEndpointPool(config.Server{
+		Endpoint:  behind,
+		Endpoints: []string{ahead, "127.0.0.1:14017"},
+	})
+	require.NoError(err)
+	t.Cleanup(p.Close)
+	client := iotexapi.NewAPIServiceClient(p)
+
+	// the most in-sync node serves the calls
+	resp, err := client.GetServerMeta(context.Background(), &iotexapi.GetServerMetaRequest{})
+	require.NoError(err)
+	require.Equal(ahead, resp.GetServerMeta().GetPackageVersion())
+
+	// the next node takes over when it goes down
+	server.Stop()
+	resp, err = client.GetServerMeta(context.Background(), &iotexapi.GetServerMetaRequest{})
+	require.NoError(err)
+	require.Equal(behind, resp.GetServerMeta().GetPackageVersion())
+	require.Equal(behind, p.candidates()[0].addr)
+}
+
+func TestEndpointPool_Close(t *testing.T) {
+	var (
+		require = require.New(t)
+		addr    = "127.0.0.1:14015"
+	)
+	newChainMetaServer(t, addr, 100)
+	p, err := newEndpointPool(config.Server{
+		Endpoint:            addr,
+		HealthCheckInterval: 10 * time.Millisecond,
+	})
+	require.NoError(err)
+
+	// the health checks and connection watchers are stopped before the
+	// connections are closed
+	closed := make(chan struct{})
+	go func() {
+		p.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		require.FailNow("the pool is not closed")
+	}
+	require.Equal(connectivity.Shutdown, p.endpoints[0].conn.GetState())
+
+	// and so is the pool of a closed client
+	cli := &grpcIoTexClient{cfg: &config.Config{Server: config.Server{Endpoint: addr}}}
+	require.NoError(cli.connect())
+	require.NoError(cli.Close())
+	require.Equal(connectivity.Shutdown, cli.pool.endpoints[0].conn.GetState())
+}
+
+func TestEndpointPool_CallTimeout(t *testing.T) {
+	var (
+		require = require.New(t)
+		addr    = "127.0.0.1:14015"
+	)
+	service := mock_iotexapi.NewMockAPIServiceServer(gomock.NewController(t))
+	service.EXPECT().
+		GetChainMeta(gomock.Any(), gomock.Any()).
+		Return(&iotexapi.GetChainMetaResponse{ChainMeta: &iotextypes.ChainMeta{Height: 1}}, nil).
+		AnyTimes()
+	// a hung node only answers when the call is cancelled
+	service.EXPECT().
+		GetServerMeta(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ *iotexapi.GetServerMetaRequest) (*iotexapi.GetServerMetaResponse, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}).
+		AnyTimes()
+	server := grpc.NewServer()
+	iotexapi.RegisterAPIServiceServer(server, service)
+	listener, err := net.Listen("tcp", addr)
+	require.NoError(err)
+	go server.Serve(listener)
+	t.Cleanup(func() {
+		server.Stop()
+		listener.Close()
+	})
+
+	cfg := testConfig()
+	cfg.Server.Endpoint = addr
+	cfg.Server.MethodTimeouts = map[string]time.Duration{"GetServerMeta": 100 * time.Millisecond}
+	cli, err := NewIoTexClient(cfg)
+	require.NoError(err)
+	_, err = cli.GetVersion(context.Background())
+	require.Equal(codes.DeadlineExceeded, status.Code(err))
+
+	// the caller's context is honoured as well
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = cli.GetStatus(ctx)
+	require.Equal(codes.Canceled, status.Code(err))
+	_, err = cli.GetLatestBlock(ctx)
+	require.Equal(codes.Canceled, status.Code(err))
+}
+
+func TestIsRetriable(t *testing.T) {
+	require := require.New(t)
+	tests := []struct {
+		err    error
+		expect bool
+	}{
+		{nil, false},
+		{errors.New("not found"), false},
+		{status.Error(codes.Unavailable, ""), true},
+		{status.Error(codes.DeadlineExceeded, ""), true},
+		{status.Error(codes.ResourceExhausted, ""), true},
+		{status.Error(codes.Aborted, ""), true},
+		{status.Error(codes.NotFound, ""), false},
+		{status.Error(codes.InvalidArgument, ""), false},
+		{status.Error(codes.Canceled, ""), false},
+		{pkgerrors.Wrap(status.Error(codes.Unavailable, ""), "wrapped"), true},
+	}
+	for i, test := range tests {
+		require.Equal(test.expect, IsRetriable(test.err), "index: %d", i)
+	}
+	for attempt := 0; attempt < 10; attempt++ {
+		delay := retryDelay(attempt)
+		require.True(delay > 0 && delay <= retryMaxDelay, "attempt: %d", attempt)
+	}
+}
+
+func TestEndpointPool_Retry(t *testing.T) {
+	var (
+		require = require.New(t)
+		addr    = "127.0.0.1:14015"
+	)
+	service := mock_iotexapi.NewMockAPIServiceServer(gomock.NewController(t))
+	service.EXPECT().
+		GetChainMeta(gomock.Any(), gomock.Any()).
+		Return(&iotexapi.GetChainMetaResponse{ChainMeta: &iotextypes.ChainMeta{Height: 1}}, nil).
+		AnyTimes()
+	// reads are retried until the node recovers
+	gomock.InOrder(
+		service.EXPECT().
+			GetServerMeta(gomock.Any(), gomock.Any()).
+			Return(nil, status.Error(codes.Unavailable, "restarting")).
+			Times(2),
+		service.EXPECT().
+			GetServerMeta(gomock.Any(), gomock.Any()).
+			Return(&iotexapi.GetServerMetaResponse{ServerMeta: &iotextypes.ServerMeta{PackageVersion: "v1"}}, nil).
+			Times(1),
+	)
+	// writes are not
+	service.EXPECT().
+		SendAction(gomock.Any(), gomock.Any()).
+		Return(nil, status.Error(codes.Unavailable, "restarting")).
+		Times(1)
+	// neither are failures of the request itself
+	service.EXPECT().
+		GetActPoolActions(gomock.Any(), gomock.Any()).
+		Return(nil, status.Error(codes.NotFound, "not found")).
+		Times(1)
+	server := grpc.NewServer()
+	iotexapi.RegisterAPIServiceServer(server, service)
+	listener, err := net.Listen("tcp", addr)
+	require.NoError(err)
+	go server.Serve(listener)
+	t.Cleanup(func() {
+		server.Stop()
+		listener.Close()
+	})
+
+	cfg := testConfig()
+	cfg.Server.Endpoint = addr
+	cli, err := NewIoTexClient(cfg)
+	require.NoError(err)
+	version, err := cli.GetVersion(context.Background())
+	require.NoError(err)
+	require.Equal("v1", version.GetServerMeta().GetPackageVersion())
+
+	_, err = cli.SubmitTx(context.Background(), &iotextypes.Action{})
+	require.True(IsRetriable(err))
+
+	_, err = cli.GetMemPool(context.Background(), nil)
+	require.False(IsRetriable(err))
+}
+
+func TestEndpointPool_NoFailover(t *testing.T) {
+	var (
+		require = require.New(t)
+		best    = "127.0.0.1:14015"
+		next    = "127.0.0.1:14016"
+	)
+	newServer := func(addr string, height uint64) *mock_iotexapi.MockAPIServiceServer {
+		service := mock_iotexapi.NewMockAPIServiceServer(gomock.NewController(t))
+		service.EXPECT().
+			GetChainMeta(gomock.Any(), gomock.Any()).
+			Return(&iotexapi.GetChainMetaResponse{ChainMeta: &iotextypes.ChainMeta{Height: height}}, nil).
+			AnyTimes()
+		server := grpc.NewServer()
+		iotexapi.RegisterAPIServiceServer(server, service)
+		listener, err := net.Listen("tcp", addr)
+		require.NoError(err)
+		go server.Serve(listener)
+		t.Cleanup(func() {
+			server.Stop()
+			listener.Close()
+		})
+		return service
+	}
+	bestService := newServer(best, 110)
+	nextService := newServer(next, 100)
+	// a write which may have reached the node is not sent to another one
+	bestService.EXPECT().
+		SendAction(gomock.Any(), gomock.Any()).
+		Return(nil, status.Error(codes.Unavailable, "restarting")).
+		Times(1)
+	nextService.EXPECT().SendAction(gomock.Any(), gomock.Any()).Times(0)
+	// a read is not retried once the caller gave up
+	bestService.EXPECT().
+		GetServerMeta(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ *iotexapi.GetServerMetaRequest) (*iotexapi.GetServerMetaResponse, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}).
+		Times(1)
+	nextService.EXPECT().GetServerMeta(gomock.Any(), gomock.Any()).Times(0)
+
+	p, err := newEndpointPool(config.Server{
+		Endpoint:  best,
+		Endpoints: []string{next},
+	})
+	require.NoError(err)
+	t.Cleanup(p.Close)
+	client := iotexapi.NewAPIServiceClient(p)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = client.GetServerMeta(ctx, &iotexapi.GetServerMetaRequest{})
+	require.Equal(codes.DeadlineExceeded, status.Code(err))
+
+	_, err = client.SendAction(context.Background(), &iotexapi.SendActionRequest{})
+	require.Equal(codes.Unavailable, status.Code(err))
+}
+
+func testForkBlock(height int64, fork, parentFork string) *types.Block {
+	return &types.Block{
+		BlockIdentifier: &types.BlockIdentifier{
+			Index: height,
+			Hash:  fork + strconv.FormatInt(height, 10),
+		},
+		ParentBlockIdentifier: &types.BlockIdentifier{
+			Index: height - 1,
+			Hash:  parentFork + strconv.FormatInt(height-1, 10),
+		},
+	}
+}
+
+func TestCachedIoTexClient(t *testing.T) {
+	var (
+		require = require.New(t)
+		ctx     = context.Background()
+		inner   = mock_client.NewMockIoTexClient(gomock.NewController(t))
+		txs     = []*types.Transaction{{
+			TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx"},
+			Operations: []*types.Operation{{
+				Account: &types.AccountIdentifier{Address: "a"},
+				Amount:  &types.Amount{Value: "1"},
+			}},
+		}}
+	)
+	cli, err := newCachedIoTexClient(inner, config.Cache{Size: 10, FinalityDepth: 3})
+	require.NoError(err)
+	var reorged []int64
+	cli.onInvalidate = func(from int64) { reorged = append(reorged, from) }
+	hits := promtestutil.ToFloat64(cacheRequests.WithLabelValues("block", "hit"))
+	inner.EXPECT().GetLatestBlock(gomock.Any()).Return(testForkBlock(6, "a", "a"), nil).Times(1)
+	_, err = cli.GetLatestBlock(ctx)
+	require.NoError(err)
+
+	// the node is asked only once per final height
+	for height := int64(1); height <= 3; height++ {
+		inner.EXPECT().GetBlock(gomock.Any(), height).Return(testForkBlock(height, "a", "a"), nil).Times(1)
+		inner.EXPECT().GetTransactions(gomock.Any(), height).Return(copyTransactions(txs), nil).Times(1)
+		for i := 0; i < 2; i++ {
+			blk, err := cli.GetBlock(ctx, height)
+			require.NoError(err)
+			require.Equal(testForkBlock(height, "a", "a"), blk)
+			ret, err := cli.GetTransactions(ctx, height)
+			require.NoError(err)
+			require.Equal(txs, ret)
+			// callers fill in the transactions of their own copy, and can't
+			// alter the cached operations
+			blk.Transactions = ret
+			ret[0].Operations[0].Amount.Value = "2"
+			ret[0].Operations = nil
+		}
+	}
+	blk, err := cli.GetBlockByHash(ctx, "a3")
+	require.NoError(err)
+	require.Equal(testForkBlock(3, "a", "a"), blk)
+
+	// the blocks which are not final are checked against the node, their
+	// transactions are kept as long as the node has the same block
+	inner.EXPECT().GetBlock(gomock.Any(), int64(5)).Return(testForkBlock(5, "a", "a"), nil).Times(3)
+	inner.EXPECT().GetTransactions(gomock.Any(), int64(5)).Return(copyTransactions(txs), nil).Times(1)
+	for i := 0; i < 2; i++ {
+		ret, err := cli.GetTransactions(ctx, 5)
+		require.NoError(err)
+		require.Equal(txs, ret)
+	}
+	blk, err = cli.GetBlock(ctx, 5)
+	require.NoError(err)
+	require.Equal("a5", blk.BlockIdentifier.Hash)
+
+	// the node now has another block at height 5, the final blocks stay
+	inner.EXPECT().GetBlock(gomock.Any(), int64(5)).Return(testForkBlock(5, "b", "a"), nil).Times(2)
+	inner.EXPECT().GetTransactions(gomock.Any(), int64(5)).Return(nil, nil).Times(1)
+	blk, err = cli.GetBlock(ctx, 5)
+	require.NoError(err)
+	require.Equal("b5", blk.BlockIdentifier.Hash)
+	require.Equal([]int64{5}, reorged)
+	ret, err := cli.GetTransactions(ctx, 5)
+	require.NoError(err)
+	require.Empty(ret)
+	blk, err = cli.GetBlock(ctx, 3)
+	require.NoError(err)
+	require.Equal("a3", blk.BlockIdentifier.Hash)
+
+	// unknown hashes go to the node
+	inner.EXPECT().GetBlockByHash(gomock.Any(), "a5").Return(nil, errors.New("not found")).Times(1)
+	_, err = cli.GetBlockByHash(ctx, "a5")
+	require.Error(err)
+	require.Equal(hits+5, promtestutil.ToFloat64(cacheRequests.WithLabelValues("block", "hit")))
+}
+
+func TestPrefetcher(t *testing.T) {
+	var (
+		require = require.New(t)
+		ctx     = context.Background()
+		chain   = testChain()
+		client  = mock_iotexapi.NewMockAPIServiceClient(gomock.NewController(t))
+		p       = newPrefetcher(10)
+	)
+	raws := make([]*iotexapi.BlockInfo, 0, len(chain)-1)
+	for _, meta := range chain[1:] {
+		raws = append(raws, &iotexapi.BlockInfo{
+			Block: &iotextypes.Block{
+				Header: &iotextypes.BlockHeader{
+					Core: &iotextypes.BlockHeaderCore{Height: meta.Height},
+				},
+			},
+		})
+	}
+	// the window is clamped to the tip and read in one go
+	client.EXPECT().
+		GetChainMeta(gomock.Any(), gomock.Any()).
+		Return(&iotexapi.GetChainMetaResponse{ChainMeta: testChainMeta(chain)}, nil).
+		Times(1)
+	client.EXPECT().
+		GetBlockMetas(gomock.Any(), &iotexapi.GetBlockMetasRequest{
+			Lookup: &iotexapi.GetBlockMetasRequest_ByIndex{
+				ByIndex: &iotexapi.GetBlockMetasByIndexRequest{Start: 1, Count: 4},
+			},
+		}).
+		Return(&iotexapi.GetBlockMetasResponse{Total: 4, BlkMetas: chain}, nil).
+		Times(1)
+	client.EXPECT().
+		GetRawBlocks(gomock.Any(), &iotexapi.GetRawBlocksRequest{
+			StartHeight:         2,
+			Count:               3,
+			WithReceipts:        true,
+			WithTransactionLogs: true,
+		}).
+		Return(&iotexapi.GetRawBlocksResponse{Blocks: raws}, nil).
+		Times(1)
+
+	// the first request is not read ahead
+	_, ok := p.block(ctx, client, 1)
+	require.False(ok)
+	for height := uint64(2); height <= 4; height++ {
+		blk, ok := p.block(ctx, client, height)
+		require.True(ok)
+		require.Equal(chain[height-2], blk.parent)
+		require.Equal(chain[height-1], blk.meta)
+		require.Equal(raws[height-2], blk.info)
+	}
+	_, ok = p.lookup(4)
+	require.True(ok)
+
+	// a reorganization drops the blocks from its height on
+	p.invalidate(4)
+	_, ok = p.lookup(4)
+	require.False(ok)
+	_, ok = p.lookup(3)
+	require.True(ok)
+
+	// a jump drops the window
+	_, ok = p.block(ctx, client, 100)
+	require.False(ok)
+	_, ok = p.lookup(4)
+	require.False(ok)
+}
+
+func TestPrefetcher_Window(t *testing.T) {
+	var (
+		require = require.New(t)
+		chain   = testChain()
+		client  = mock_iotexapi.NewMockAPIServiceClient(gomock.NewController(t))
+		p       = newPrefetcher(8)
+	)
+	fetch := func(ctx context.Context, height uint64) {
+		p.Lock()
+		p.last, p.streak = height-1, sequentialThreshold
+		p.Unlock()
+		p.block(ctx, client, height)
+	}
+	tip := &iotexapi.GetChainMetaResponse{ChainMeta: testChainMeta(chain)}
+
+	// neither a cancelled request nor a height above the tip shrinks it
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client.EXPECT().GetChainMeta(gomock.Any(), gomock.Any()).Return(nil, context.Canceled).Times(1)
+	fetch(ctx, 4)
+	require.Equal(uint64(8), p.window)
+	client.EXPECT().GetChainMeta(gomock.Any(), gomock.Any()).Return(tip, nil).Times(1)
+	fetch(context.Background(), 5)
+	require.Equal(uint64(8), p.window)
+
+	// a failed read does, the window grows back after a successful one
+	client.EXPECT().GetChainMeta(gomock.Any(), gomock.Any()).Return(tip, nil).Times(2)
+	client.EXPECT().GetBlockMetas(gomock.Any(), gomock.Any()).Return(nil, errors.New("too large")).Times(1)
+	fetch(context.Background(), 4)
+	require.Equal(uint64(4), p.window)
+	client.EXPECT().
+		GetBlockMetas(gomock.Any(), gomock.Any()).
+		Return(&iotexapi.GetBlockMetasResponse{Total: 2, BlkMetas: chain[2:4]}, nil).
+		Times(1)
+	client.EXPECT().
+		GetRawBlocks(gomock.Any(), gomock.Any()).
+		Return(&iotexapi.GetRawBlocksResponse{Blocks: []*iotexapi.BlockInfo{{
+			Block: &iotextypes.Block{
+				Header: &iotextypes.BlockHeader{
+					Core: &iotextypes.BlockHeaderCore{Height: chain[3].Height},
+				},
+			},
+		}}}, nil).
+		Times(1)
+	fetch(context.Background(), 4)
+	require.Equal(uint64(8), p.window)
+	blk, ok := p.lookup(4)
+	require.True(ok)
+	require.True(blk.withLogs)
+
+	// nor does a range without transaction logs, it is read without them
+	p.invalidate(4)
+	client.EXPECT().GetChainMeta(gomock.Any(), gomock.Any()).Return(tip, nil).Times(1)
+	client.EXPECT().
+		GetBlockMetas(gomock.Any(), gomock.Any()).
+		Return(&iotexapi.GetBlockMetasResponse{Total: 2, BlkMetas: chain[2:4]}, nil).
+		Times(1)
+	gomock.InOrder(
+		client.EXPECT().
+			GetRawBlocks(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *iotexapi.GetRawBlocksRequest, _ ...grpc.CallOption) (*iotexapi.GetRawBlocksResponse, error) {
+				require.True(req.WithTransactionLogs)
+				return nil, status.Error(codes.NotFound, "transaction log not found")
+			}).
+			Times(1),
+		client.EXPECT().
+			GetRawBlocks(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *iotexapi.GetRawBlocksRequest, _ ...grpc.CallOption) (*iotexapi.GetRawBlocksResponse, error) {
+				require.False(req.WithTransactionLogs)
+				return &iotexapi.GetRawBlocksResponse{Blocks: []*iotexapi.BlockInfo{{
+					Block: &iotextypes.Block{
+						Header: &iotextypes.BlockHeader{
+							Core: &iotextypes.BlockHeaderCore{Height: chain[3].Height},
+						},
+					},
+				}}}, nil
+			}).
+			Times(1),
+	)
+	fetch(context.Background(), 4)
+	require.Equal(uint64(8), p.window)
+	blk, ok = p.lookup(4)
+	require.True(ok)
+	require.False(blk.withLogs)
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2020 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package iotex_client
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+var (
+	upstreamCallDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "iotex_rosetta_upstream_call_duration_seconds",
+			Help:    "Latency of the gRPC calls to the nodes.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"endpoint", "method"},
+	)
+	upstreamCallErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "iotex_rosetta_upstream_call_errors_total",
+			Help: "Failed gRPC calls to the nodes by status code.",
+		},
+		[]string{"endpoint", "method", "code"},
+	)
+	upstreamConnects = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "iotex_rosetta_upstream_connects_total",
+			Help: "Attempts to set up the connections to the nodes.",
+		},
+		[]string{"result"},
+	)
+	upstreamStateTransitions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "iotex_rosetta_upstream_connection_state_transitions_total",
+			Help: "Transitions of the node connections into each state.",
+		},
+		[]string{"endpoint", "state"},
+	)
+	upstreamHealthy = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iotex_rosetta_upstream_healthy",
+			Help: "Whether the node passed its last health check.",
+		},
+		[]string{"endpoint"},
+	)
+	upstreamTipHeight = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "iotex_rosetta_upstream_tip_height",
+			Help: "Tip height of the node at its last health check.",
+		},
+		[]string{"endpoint"},
+	)
+	cacheRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "iotex_rosetta_cache_requests_total",
+			Help: "Lookups of the block and transaction caches.",
+		},
+		[]string{"cache", "result"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		upstreamCallDuration,
+		upstreamCallErrors,
+		upstreamConnects,
+		upstreamStateTransitions,
+		upstreamHealthy,
+		upstreamTipHeight,
+		cacheRequests,
+	)
+}
+
+// observeCacheLookup counts a hit or a miss of the cache.
+func observeCacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+// watchConnState counts the state transitions of the connection until it is
+// closed or ctx is done.
+func watchConnState(ctx context.Context, addr string, conn *grpc.ClientConn) {
+	state := conn.GetState()
+	for state != connectivity.Shutdown && conn.WaitForStateChange(ctx, state) {
+		state = conn.GetState()
+		upstreamStateTransitions.WithLabelValues(addr, state.String()).Inc()
+	}
+}
@@ -38,6 +38,35 @@ func (m *MockIoTexClient) EXPECT() *MockIoTexClientMockRecorder {
 	return m.recorder
 }
 
+// Close mocks base method.
+func (m *MockIoTexClient) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockIoTexClientMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockIoTexClient)(nil).Close))
+}
+
+// EstimateExecutionGas mocks base method.
+func (m *MockIoTexClient) EstimateExecutionGas(ctx context.Context, caller string, execution *iotextypes.Execution) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EstimateExecutionGas", ctx, caller, execution)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EstimateExecutionGas indicates an expected call of EstimateExecutionGas.
+func (mr *MockIoTexClientMockRecorder) EstimateExecutionGas(ctx, caller, execution interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateExecutionGas", reflect.TypeOf((*MockIoTexClient)(nil).EstimateExecutionGas), ctx, caller, execution)
+}
+
 // EstimateGasForAction mocks base method.
 func (m *MockIoTexClient) EstimateGasForAction(ctx context.Context, action *iotextypes.Action) (uint64, error) {
 	m.ctrl.T.Helper()
@@ -54,18 +83,18 @@ func (mr *MockIoTexClientMockRecorder) EstimateGasForAction(ctx, action interfac
 }
 
 // GetAccount mocks base method.
-func (m *MockIoTexClient) GetAccount(ctx context.Context, height int64, owner string) (*types.AccountBalanceResponse, error) {
+func (m *MockIoTexClient) GetAccount(ctx context.Context, height int64, owner string, currencies []*types.Currency) (*types.AccountBalanceResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccount", ctx, height, owner)
+	ret := m.ctrl.Call(m, "GetAccount", ctx, height, owner, currencies)
 	ret0, _ := ret[0].(*types.AccountBalanceResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAccount indicates an expected call of GetAccount.
-func (mr *MockIoTexClientMockRecorder) GetAccount(ctx, height, owner interface{}) *gomock.Call {
+func (mr *MockIoTexClientMockRecorder) GetAccount(ctx, height, owner, currencies interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockIoTexClient)(nil).GetAccount), ctx, height, owner)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockIoTexClient)(nil).GetAccount), ctx, height, owner, currencies)
 }
 
 // GetBlock mocks base method.
@@ -83,6 +112,21 @@ func (mr *MockIoTexClientMockRecorder) GetBlock(ctx, height interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlock", reflect.TypeOf((*MockIoTexClient)(nil).GetBlock), ctx, height)
 }
 
+// GetBlockByHash mocks base method.
+func (m *MockIoTexClient) GetBlockByHash(ctx context.Context, hash string) (*types.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockByHash", ctx, hash)
+	ret0, _ := ret[0].(*types.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockByHash indicates an expected call of GetBlockByHash.
+func (mr *MockIoTexClientMockRecorder) GetBlockByHash(ctx, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockByHash", reflect.TypeOf((*MockIoTexClient)(nil).GetBlockByHash), ctx, hash)
+}
+
 // GetBlockTransaction mocks base method.
 func (m *MockIoTexClient) GetBlockTransaction(ctx context.Context, actionHash string) (*types.Transaction, error) {
 	m.ctrl.T.Helper()
@@ -202,6 +246,36 @@ func (mr *MockIoTexClientMockRecorder) GetStatus(ctx interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatus", reflect.TypeOf((*MockIoTexClient)(nil).GetStatus), ctx)
 }
 
+// GetSubAccount mocks base method.
+func (m *MockIoTexClient) GetSubAccount(ctx context.Context, height int64, owner, subAccount string) (*types.AccountBalanceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubAccount", ctx, height, owner, subAccount)
+	ret0, _ := ret[0].(*types.AccountBalanceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubAccount indicates an expected call of GetSubAccount.
+func (mr *MockIoTexClientMockRecorder) GetSubAccount(ctx, height, owner, subAccount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubAccount", reflect.TypeOf((*MockIoTexClient)(nil).GetSubAccount), ctx, height, owner, subAccount)
+}
+
+// GetTargetHeight mocks base method.
+func (m *MockIoTexClient) GetTargetHeight() (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTargetHeight")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTargetHeight indicates an expected call of GetTargetHeight.
+func (mr *MockIoTexClientMockRecorder) GetTargetHeight() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTargetHeight", reflect.TypeOf((*MockIoTexClient)(nil).GetTargetHeight))
+}
+
 // GetTransactions mocks base method.
 func (m *MockIoTexClient) GetTransactions(ctx context.Context, height int64) ([]*types.Transaction, error) {
 	m.ctrl.T.Helper()
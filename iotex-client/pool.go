@@ -0,0 +1,310 @@
+// Copyright (c) 2020 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package iotex_client
+
+import (
+	"context"
+	"crypto/tls"
+	"log"
+	"math/rand"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+
+	"github.com/iotexproject/iotex-core-rosetta-gateway/config"
+)
+
+const (
+	defaultMaxHeightLag        = 5
+	defaultHealthCheckInterval = 10 * time.Second
+	healthCheckTimeout         = 5 * time.Second
+
+	// retries of idempotent calls with jittered exponential backoff
+	maxRetries     = 3
+	retryBaseDelay = 100 * time.Millisecond
+	retryMaxDelay  = 2 * time.Second
+)
+
+// nonIdempotentMethods are the calls which must not be retried blindly.
+var nonIdempotentMethods = map[string]bool{
+	"SendAction": true,
+}
+
+type (
+	// endpoint is a node of the pool and its last known status.
+	endpoint struct {
+		addr    string
+		conn    *grpc.ClientConn
+		client  iotexapi.APIServiceClient
+		height  uint64
+		healthy bool
+	}
+
+	// endpointPool is a grpc.ClientConnInterface which routes calls to the
+	// most in-sync healthy node and fails over to the others on errors.
+	endpointPool struct {
+		sync.RWMutex
+
+		endpoints    []*endpoint
+		maxHeightLag uint64
+		callTimeout  func(method string) time.Duration
+
+		// the background routines run until the pool is closed
+		ctx    context.Context
+		cancel context.CancelFunc
+		wg     sync.WaitGroup
+	}
+)
+
+var _ grpc.ClientConnInterface = (*endpointPool)(nil)
+
+// newEndpointPool dials the configured endpoints and checks their health,
+// the nodes are checked again periodically in the background until the pool
+// is closed.
+func newEndpointPool(cfg config.Server) (*endpointPool, error) {
+	addrs := cfg.EndpointList()
+	if len(addrs) == 0 {
+		return nil, errors.New("no endpoint is configured")
+	}
+	opts := []grpc.DialOption{}
+	if cfg.SecureEndpoint {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+	p := &endpointPool{
+		endpoints:    make([]*endpoint, 0, len(addrs)),
+		maxHeightLag: cfg.MaxHeightLag,
+		callTimeout:  cfg.CallTimeout,
+	}
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+	if p.maxHeightLag == 0 {
+		p.maxHeightLag = defaultMaxHeightLag
+	}
+	for _, addr := range addrs {
+		conn, err := grpc.Dial(addr, opts...)
+		if err != nil {
+			p.Close()
+			return nil, errors.Wrapf(err, "failed to dial %s", addr)
+		}
+		p.endpoints = append(p.endpoints, &endpoint{
+			addr:   addr,
+			conn:   conn,
+			client: iotexapi.NewAPIServiceClient(conn),
+		})
+		p.wg.Add(1)
+		go func(addr string, conn *grpc.ClientConn) {
+			defer p.wg.Done()
+			watchConnState(p.ctx, addr, conn)
+		}(addr, conn)
+	}
+	p.checkHealth()
+
+	interval := cfg.HealthCheckInterval
+	if interval <= 0 {
+		interval = defaultHealthCheckInterval
+	}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.ctx.Done():
+				return
+			case <-ticker.C:
+				p.checkHealth()
+			}
+		}
+	}()
+	return p, nil
+}
+
+// checkHealth updates the tip height of every node.
+func (p *endpointPool) checkHealth() {
+	var wg sync.WaitGroup
+	for _, e := range p.endpoints {
+		wg.Add(1)
+		go func(e *endpoint) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+			defer cancel()
+			resp, err := e.client.GetChainMeta(ctx, &iotexapi.GetChainMetaRequest{})
+			p.Lock()
+			defer p.Unlock()
+			if err != nil {
+				if e.healthy {
+					log.Printf("endpoint %s is unhealthy: %v\n", e.addr, err)
+				}
+				e.healthy = false
+				upstreamHealthy.WithLabelValues(e.addr).Set(0)
+				return
+			}
+			e.healthy = true
+			e.height = resp.GetChainMeta().GetHeight()
+			upstreamHealthy.WithLabelValues(e.addr).Set(1)
+			upstreamTipHeight.WithLabelValues(e.addr).Set(float64(e.height))
+		}(e)
+	}
+	wg.Wait()
+}
+
+// candidates returns the nodes in the order they should be tried, the in-sync
+// healthy nodes come first with the highest one in front, then the lagging
+// nodes and at last the unhealthy ones.
+func (p *endpointPool) candidates() []*endpoint {
+	p.RLock()
+	defer p.RUnlock()
+	tip := p.tip()
+	rank := func(e *endpoint) int {
+		switch {
+		case !e.healthy:
+			return 2
+		case e.height+p.maxHeightLag < tip:
+			return 1
+		default:
+			return 0
+		}
+	}
+	ret := make([]*endpoint, len(p.endpoints))
+	copy(ret, p.endpoints)
+	sort.SliceStable(ret, func(i, j int) bool {
+		ri, rj := rank(ret[i]), rank(ret[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return ri == 0 && ret[i].height > ret[j].height
+	})
+	return ret
+}
+
+// tipHeight returns the highest tip height of the healthy nodes at their last
+// health check, 0 if no node is healthy.
+func (p *endpointPool) tipHeight() uint64 {
+	p.RLock()
+	defer p.RUnlock()
+	return p.tip()
+}
+
+func (p *endpointPool) tip() uint64 {
+	var tip uint64
+	for _, e := range p.endpoints {
+		if e.healthy && e.height > tip {
+			tip = e.height
+		}
+	}
+	return tip
+}
+
+func (p *endpointPool) markUnhealthy(e *endpoint, err error) {
+	p.Lock()
+	defer p.Unlock()
+	if e.healthy {
+		log.Printf("endpoint %s is unhealthy: %v\n", e.addr, err)
+	}
+	e.healthy = false
+	upstreamHealthy.WithLabelValues(e.addr).Set(0)
+}
+
+// Invoke performs the unary call on the best node, and retries it on the
+// next one if the node is unreachable or does not answer in time. Idempotent
+// calls are retried with backoff when all the nodes failed transiently, none
+// is retried once the caller's context is done.
+func (p *endpointPool) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) (err error) {
+	// method is in the form of /iotexapi.APIService/GetChainMeta
+	name := path.Base(method)
+	for attempt := 0; ; attempt++ {
+		err = p.failover(ctx, name, method, args, reply, opts...)
+		if err == nil || ctx.Err() != nil || attempt >= maxRetries || nonIdempotentMethods[name] || !IsRetriable(err) {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryDelay(attempt)):
+		}
+	}
+}
+
+// failover tries the nodes one after another until one answers. A
+// non-idempotent call is only tried on the best node, it may have been
+// performed even if the node failed.
+func (p *endpointPool) failover(ctx context.Context, name, method string, args, reply interface{}, opts ...grpc.CallOption) (err error) {
+	for _, e := range p.candidates() {
+		err = p.invoke(ctx, e, name, method, args, reply, opts...)
+		if err == nil || ctx.Err() != nil || !IsRetriable(err) {
+			return
+		}
+		p.markUnhealthy(e, err)
+		if nonIdempotentMethods[name] {
+			return
+		}
+	}
+	return
+}
+
+func (p *endpointPool) invoke(ctx context.Context, e *endpoint, name, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	if timeout := p.callTimeout(name); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	err := e.conn.Invoke(ctx, method, args, reply, opts...)
+	upstreamCallDuration.WithLabelValues(e.addr, name).Observe(time.Since(start).Seconds())
+	if err != nil {
+		upstreamCallErrors.WithLabelValues(e.addr, name, status.Code(err).String()).Inc()
+	}
+	return err
+}
+
+// NewStream opens the stream on the best node.
+func (p *endpointPool) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return p.candidates()[0].conn.NewStream(ctx, desc, method, opts...)
+}
+
+// Close stops the background health checks and connection watchers, and
+// closes the connections to the nodes.
+func (p *endpointPool) Close() {
+	p.cancel()
+	p.wg.Wait()
+	for _, e := range p.endpoints {
+		e.conn.Close()
+	}
+}
+
+// IsRetriable tells if the node call failed because of the node rather than
+// the request, in which case the call may succeed later or on another node.
+func IsRetriable(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch status.Code(errors.Cause(err)) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	}
+	return false
+}
+
+// retryDelay returns the full jittered delay before the next attempt.
+func retryDelay(attempt int) time.Duration {
+	delay := retryBaseDelay << uint(attempt)
+	if delay <= 0 || delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return time.Duration(rand.Int63n(int64(delay)) + 1)
+}
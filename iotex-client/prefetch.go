@@ -0,0 +1,235 @@
+// Copyright (c) 2020 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package iotex_client
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+)
+
+const (
+	defaultPrefetchWindow = 20
+	// sequentialThreshold is the number of heights requested one after
+	// another before reading ahead
+	sequentialThreshold = 2
+)
+
+type (
+	// prefetchedBlock is a block read ahead along with its parent meta.
+	prefetchedBlock struct {
+		parent *iotextypes.BlockMeta
+		meta   *iotextypes.BlockMeta
+		info   *iotexapi.BlockInfo
+		// withLogs tells if info carries the transaction logs of the block
+		withLogs bool
+	}
+
+	// prefetcher notices blocks requested in order and reads a window of the
+	// following ones ahead, with two batched calls instead of three per
+	// height.
+	prefetcher struct {
+		sync.Mutex
+
+		fetchMu sync.Mutex
+		// window shrinks when the node fails to serve it and grows back up
+		// to maxWindow
+		window    uint64
+		maxWindow uint64
+		last      uint64
+		streak    int
+		blocks    map[uint64]*prefetchedBlock
+	}
+)
+
+// errAboveTip is returned when the blocks to read ahead are not produced yet.
+var errAboveTip = errors.New("height is above the tip")
+
+func newPrefetcher(window uint64) *prefetcher {
+	if window == 0 {
+		window = defaultPrefetchWindow
+	}
+	return &prefetcher{
+		window:    window,
+		maxWindow: window,
+		blocks:    make(map[uint64]*prefetchedBlock),
+	}
+}
+
+// block returns the block of the height if it was read ahead, a new window is
+// read when the blocks are requested in order.
+func (p *prefetcher) block(ctx context.Context, client iotexapi.APIServiceClient, height uint64) (*prefetchedBlock, bool) {
+	blk, ok, sequential := p.track(height)
+	if ok || sequential {
+		observeCacheLookup("prefetch", ok)
+	}
+	if ok || !sequential {
+		return blk, ok
+	}
+	// a single window is read at a time, concurrent requests wait for it
+	p.fetchMu.Lock()
+	defer p.fetchMu.Unlock()
+	if blk, ok := p.lookup(height); ok {
+		return blk, true
+	}
+	p.Lock()
+	window := p.window
+	p.Unlock()
+	blocks, err := fetchBlocks(ctx, client, height, window)
+	if err != nil {
+		log.Printf("failed to prefetch %d blocks from height %d: %v\n", window, height, err)
+		// the node may reject too large responses, read less next time, a
+		// cancelled request or a missing block says nothing of the size
+		if ctx.Err() == nil && errors.Cause(err) != errAboveTip {
+			p.Lock()
+			if p.window > 1 {
+				p.window /= 2
+			}
+			p.Unlock()
+		}
+		return nil, false
+	}
+	p.Lock()
+	defer p.Unlock()
+	if p.window < p.maxWindow {
+		p.window *= 2
+		if p.window > p.maxWindow {
+			p.window = p.maxWindow
+		}
+	}
+	// the blocks read before are dropped if the window doesn't link to them
+	if prev, ok := p.blocks[height-1]; ok && prev.meta.GetHash() != blocks[0].parent.GetHash() {
+		p.blocks = make(map[uint64]*prefetchedBlock)
+	}
+	for _, b := range blocks {
+		p.blocks[b.meta.Height] = b
+	}
+	blk, ok = p.blocks[height]
+	return blk, ok
+}
+
+// track records a request of the height and returns its block if it was read
+// ahead. Heights requested close to each other, as concurrent syncers do, are
+// still considered sequential.
+func (p *prefetcher) track(height uint64) (*prefetchedBlock, bool, bool) {
+	p.Lock()
+	defer p.Unlock()
+	switch {
+	case height > p.last && height <= p.last+p.window:
+		p.streak++
+		p.last = height
+	case height > p.last || height+p.window < p.last:
+		p.streak = 0
+		p.last = height
+		p.blocks = make(map[uint64]*prefetchedBlock)
+	}
+	for h := range p.blocks {
+		if h+p.window < p.last {
+			delete(p.blocks, h)
+		}
+	}
+	blk, ok := p.blocks[height]
+	return blk, ok, p.streak >= sequentialThreshold
+}
+
+// lookup returns the block of the height if it was read ahead, without
+// counting it as a request.
+func (p *prefetcher) lookup(height uint64) (*prefetchedBlock, bool) {
+	p.Lock()
+	defer p.Unlock()
+	blk, ok := p.blocks[height]
+	return blk, ok
+}
+
+// invalidate drops the blocks read ahead from the height on, the chain was
+// reorganized there.
+func (p *prefetcher) invalidate(from uint64) {
+	p.Lock()
+	defer p.Unlock()
+	for h := range p.blocks {
+		if h >= from {
+			delete(p.blocks, h)
+		}
+	}
+}
+
+// fetchBlocks reads at most count blocks from the height on, along with
+// their receipts and transaction logs. The node fails the whole range if one
+// of its blocks has no transaction log, as the blocks before the logs were
+// indexed, the range is then read without the logs.
+func fetchBlocks(ctx context.Context, client iotexapi.APIServiceClient, height, count uint64) ([]*prefetchedBlock, error) {
+	chainMeta, err := client.GetChainMeta(ctx, &iotexapi.GetChainMetaRequest{})
+	if err != nil {
+		return nil, err
+	}
+	tip := chainMeta.GetChainMeta().GetHeight()
+	if height > tip {
+		return nil, errors.Wrapf(errAboveTip, "height %d, tip %d", height, tip)
+	}
+	if height+count-1 > tip {
+		count = tip - height + 1
+	}
+
+	// the parent of the first block is fetched as well, the genesis block is
+	// its own parent
+	start, metaCount := height-1, count+1
+	if height <= 1 {
+		start, metaCount = 1, count
+	}
+	metas, err := client.GetBlockMetas(ctx, &iotexapi.GetBlockMetasRequest{
+		Lookup: &iotexapi.GetBlockMetasRequest_ByIndex{
+			ByIndex: &iotexapi.GetBlockMetasByIndexRequest{
+				Start: start,
+				Count: metaCount,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	request := &iotexapi.GetRawBlocksRequest{
+		StartHeight:         height,
+		Count:               count,
+		WithReceipts:        true,
+		WithTransactionLogs: true,
+	}
+	raws, err := client.GetRawBlocks(ctx, request)
+	if status.Code(err) == codes.NotFound {
+		request.WithTransactionLogs = false
+		raws, err = client.GetRawBlocks(ctx, request)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(metas.GetBlkMetas())) != metaCount || uint64(len(raws.GetBlocks())) != count {
+		return nil, errors.New("unexpected number of blocks")
+	}
+
+	ret := make([]*prefetchedBlock, 0, count)
+	parent := metas.GetBlkMetas()[0]
+	for i, info := range raws.GetBlocks() {
+		meta := metas.GetBlkMetas()[uint64(len(metas.GetBlkMetas()))-count+uint64(i)]
+		if meta.GetHeight() != info.GetBlock().GetHeader().GetCore().GetHeight() {
+			return nil, errors.New("block metas don't match raw blocks")
+		}
+		ret = append(ret, &prefetchedBlock{
+			parent:   parent,
+			meta:     meta,
+			info:     info,
+			withLogs: request.WithTransactionLogs,
+		})
+		parent = meta
+	}
+	return ret, nil
+}
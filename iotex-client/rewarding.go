@@ -0,0 +1,105 @@
+// Copyright (c) 2020 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package iotex_client
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-core/action/protocol/rewarding/rewardingpb"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+)
+
+const (
+	// RewardsSubAccount is the sub-account holding the unclaimed rewards of
+	// an account, rewards stay in the rewarding pool until they are claimed.
+	RewardsSubAccount = "rewards"
+
+	// rewardingProtocolID is the ID of the rewarding protocol
+	rewardingProtocolID = "rewarding"
+)
+
+var (
+	// operation types of the reward payouts of grant reward actions
+	BlockRewardType     = rewardingpb.RewardLog_BLOCK_REWARD.String()
+	EpochRewardType     = rewardingpb.RewardLog_EPOCH_REWARD.String()
+	FoundationBonusType = rewardingpb.RewardLog_FOUNDATION_BONUS.String()
+
+	// address of the rewarding protocol which emits the reward logs
+	rewardingProtocolAddr = func() string {
+		h := hash.Hash160b([]byte(rewardingProtocolID))
+		addr, _ := address.FromBytes(h[:])
+		return addr.String()
+	}()
+)
+
+// rewardOperations returns the operations of the reward payouts logged in the
+// receipt of a grant reward action.
+func (c *grpcIoTexClient) rewardOperations(receipt *iotextypes.Receipt) []*types.Operation {
+	ops := make([]*types.Operation, 0)
+	for _, l := range receipt.GetLogs() {
+		if l.GetContractAddress() != rewardingProtocolAddr {
+			continue
+		}
+		rewardLog := &rewardingpb.RewardLog{}
+		if err := proto.Unmarshal(l.GetData(), rewardLog); err != nil {
+			continue
+		}
+		ops = append(ops, &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				NetworkIndex: nil,
+			},
+			RelatedOperations: nil,
+			Type:              rewardLog.GetType().String(),
+			Status:            types.String(StatusSuccess),
+			// the rewards stay unclaimed in the rewarding pool, they are
+			// credited to the rewards sub-account until they are claimed
+			Account: &types.AccountIdentifier{
+				Address: rewardLog.GetAddr(),
+				SubAccount: &types.SubAccountIdentifier{
+					Address: RewardsSubAccount,
+				},
+				Metadata: nil,
+			},
+			Amount: &types.Amount{
+				Value: rewardLog.GetAmount(),
+				Currency: &types.Currency{
+					Symbol:   c.cfg.Currency.Symbol,
+					Decimals: c.cfg.Currency.Decimals,
+					Metadata: nil,
+				},
+				Metadata: nil,
+			},
+			Metadata: nil,
+		})
+	}
+	return ops
+}
+
+// unclaimedBalance returns the rewards of the owner which are not claimed yet
+// at the tip, along with the tip block.
+func (c *grpcIoTexClient) unclaimedBalance(ctx context.Context, owner string) (*big.Int, *iotextypes.BlockIdentifier, error) {
+	resp, err := c.client.ReadState(ctx, &iotexapi.ReadStateRequest{
+		ProtocolID: []byte(rewardingProtocolID),
+		MethodName: []byte("UnclaimedBalance"),
+		Arguments:  [][]byte{[]byte(owner)},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	balance, ok := new(big.Int).SetString(string(resp.GetData()), 10)
+	if !ok {
+		return nil, nil, errors.Errorf("invalid unclaimed balance %s", resp.GetData())
+	}
+	return balance, resp.GetBlockIdentifier(), nil
+}
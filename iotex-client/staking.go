@@ -0,0 +1,186 @@
+// Copyright (c) 2020 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package iotex_client
+
+import (
+	"context"
+	"encoding/binary"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/golang/protobuf/proto"
+	"github.com/iotexproject/go-pkgs/hash"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotexapi"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+)
+
+const (
+	// bucketSubAccountPrefix prefixes the index of a bucket in the name of
+	// its sub-account.
+	bucketSubAccountPrefix = "bucket:"
+
+	// stakingProtocolID is the ID of the native staking protocol
+	stakingProtocolID = "staking"
+	// bucketsPageSize is the number of buckets read at once
+	bucketsPageSize = 1000
+	// topicLength is the length of the topics of a log
+	topicLength = 32
+)
+
+// address of the staking protocol which emits the staking logs
+var stakingProtocolAddr = func() string {
+	h := hash.Hash160b([]byte(stakingProtocolID))
+	addr, _ := address.FromBytes(h[:])
+	return addr.String()
+}()
+
+// BucketSubAccount returns the sub-account of the bucket at the index, which
+// is credited and debited by the operations moving funds in and out of the
+// bucket.
+//
+// No funds move when the ownership of a bucket is transferred and the block
+// does not carry the amount of the bucket, so the transfer has no operations:
+// the operations keep the amount on the sub-account of the former owner while
+// the node reports it on the one of the new owner.
+func BucketSubAccount(index uint64) string {
+	return bucketSubAccountPrefix + strconv.FormatUint(index, 10)
+}
+
+// parseBucketSubAccount returns the bucket index of a bucket sub-account.
+func parseBucketSubAccount(subAccount string) (uint64, bool) {
+	if !strings.HasPrefix(subAccount, bucketSubAccountPrefix) {
+		return 0, false
+	}
+	index, err := strconv.ParseUint(strings.TrimPrefix(subAccount, bucketSubAccountPrefix), 10, 64)
+	return index, err == nil
+}
+
+// stakingBalance returns the balance of the bucket sub-account of the owner
+// at the tip, along with the tip block.
+func (c *grpcIoTexClient) stakingBalance(ctx context.Context, owner, subAccount string) (*big.Int, *iotextypes.BlockIdentifier, error) {
+	index, ok := parseBucketSubAccount(subAccount)
+	if !ok {
+		return nil, nil, errors.Wrap(ErrUnknownSubAccount, subAccount)
+	}
+	buckets, blk, err := c.bucketsByVoter(ctx, owner)
+	if err != nil {
+		return nil, nil, err
+	}
+	balance := new(big.Int)
+	for _, bucket := range buckets {
+		if bucket.GetIndex() != index {
+			continue
+		}
+		amount, ok := new(big.Int).SetString(bucket.GetStakedAmount(), 10)
+		if !ok {
+			return nil, nil, errors.Errorf("invalid amount %s of bucket %d", bucket.GetStakedAmount(), bucket.GetIndex())
+		}
+		balance.Add(balance, amount)
+	}
+	return balance, blk, nil
+}
+
+// bucketsByVoter returns the buckets owned by the voter at the tip, along
+// with the tip block. The withdrawn buckets are gone from the staking state.
+func (c *grpcIoTexClient) bucketsByVoter(ctx context.Context, voter string) ([]*iotextypes.VoteBucket, *iotextypes.BlockIdentifier, error) {
+	method, err := proto.Marshal(&iotexapi.ReadStakingDataMethod{
+		Method: iotexapi.ReadStakingDataMethod_BUCKETS_BY_VOTER,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		buckets []*iotextypes.VoteBucket
+		blk     *iotextypes.BlockIdentifier
+	)
+	for offset := uint32(0); ; offset += bucketsPageSize {
+		arg, err := proto.Marshal(&iotexapi.ReadStakingDataRequest{
+			Request: &iotexapi.ReadStakingDataRequest_BucketsByVoter{
+				BucketsByVoter: &iotexapi.ReadStakingDataRequest_VoteBucketsByVoter{
+					VoterAddress: voter,
+					Pagination:   &iotexapi.PaginationParam{Offset: offset, Limit: bucketsPageSize},
+				},
+			},
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		resp, err := c.client.ReadState(ctx, &iotexapi.ReadStateRequest{
+			ProtocolID: []byte(stakingProtocolID),
+			MethodName: method,
+			Arguments:  [][]byte{arg},
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		// the pages must be read at the same height
+		if blk != nil && resp.GetBlockIdentifier().GetHeight() != blk.GetHeight() {
+			return nil, nil, errors.New("the staking state changed while reading the buckets")
+		}
+		blk = resp.GetBlockIdentifier()
+		page := &iotextypes.VoteBucketList{}
+		if err := proto.Unmarshal(resp.GetData(), page); err != nil {
+			return nil, nil, err
+		}
+		buckets = append(buckets, page.GetBuckets()...)
+		if len(page.GetBuckets()) < bucketsPageSize {
+			return buckets, blk, nil
+		}
+	}
+}
+
+// bucketAccount returns the sub-account of the bucket which the transaction
+// log moves funds in or out of, nil if it is not a bucket transaction. The
+// bucket index is read from the staking log of the receipt.
+//
+// The staking logs carry the bucket index in their topics since the Fairbank
+// migration only, the bucket transactions of the blocks before it, if any,
+// have no bucket sub-account and stay on the bucket pool address.
+func bucketAccount(t *iotextypes.TransactionLog_Transaction, receipt *iotextypes.Receipt) *types.AccountIdentifier {
+	var topics [][]byte
+	for _, l := range receipt.GetLogs() {
+		if l.GetContractAddress() == stakingProtocolAddr {
+			topics = l.GetTopics()
+			break
+		}
+	}
+	// the topics are the handler name, the bucket index and the addresses
+	// involved, all right aligned to 32 bytes
+	if len(topics) < 2 || len(topics[1]) != topicLength {
+		return nil
+	}
+	var owner string
+	switch t.GetType() {
+	case iotextypes.TransactionLogType_CREATE_BUCKET:
+		owner = t.GetSender()
+	case iotextypes.TransactionLogType_WITHDRAW_BUCKET:
+		owner = t.GetRecipient()
+	case iotextypes.TransactionLogType_DEPOSIT_TO_BUCKET, iotextypes.TransactionLogType_CANDIDATE_SELF_STAKE:
+		// the depositor and the registrant may not own the bucket, the
+		// owner is the topic after the index
+		if len(topics) < 3 || len(topics[2]) != topicLength {
+			return nil
+		}
+		addr, err := address.FromBytes(topics[2][topicLength-20:])
+		if err != nil {
+			return nil
+		}
+		owner = addr.String()
+	default:
+		return nil
+	}
+	return &types.AccountIdentifier{
+		Address: owner,
+		SubAccount: &types.SubAccountIdentifier{
+			Address: BucketSubAccount(binary.BigEndian.Uint64(topics[1][topicLength-8:])),
+		},
+	}
+}
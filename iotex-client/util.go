@@ -9,11 +9,18 @@ package iotex_client
 import (
 	"context"
 	"encoding/hex"
+	"math/big"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/sha3"
 
 	"github.com/iotexproject/go-pkgs/crypto"
+	"github.com/iotexproject/go-pkgs/hash"
 	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-core/action"
 	"github.com/iotexproject/iotex-proto/golang/iotexapi"
 	"github.com/iotexproject/iotex-proto/golang/iotextypes"
 )
@@ -24,6 +31,8 @@ const (
 	// NonceKey is the name of the key in the Metadata map inside a
 	// ConstructionMetadataResponse that specifies the next valid nonce.
 	NonceKey = "nonce"
+	// readContractGasLimit is the gas limit of read only contract calls
+	readContractGasLimit = 1000000
 )
 
 func fillIndex(transactions []*types.Transaction) []*types.Transaction {
@@ -41,7 +50,6 @@ func fillIndex(transactions []*types.Transaction) []*types.Transaction {
 
 func getTransactionLog(ctx context.Context, height int64, client iotexapi.APIServiceClient) (
 	transferLogMap map[string][]*iotextypes.TransactionLog_Transaction, err error) {
-	transferLogMap = make(map[string][]*iotextypes.TransactionLog_Transaction)
 	transferLog, err := client.GetTransactionLogByBlockHeight(
 		ctx,
 		&iotexapi.GetTransactionLogByBlockHeightRequest{BlockHeight: uint64(height)},
@@ -49,12 +57,17 @@ func getTransactionLog(ctx context.Context, height int64, client iotexapi.APISer
 	if err != nil {
 		return nil, err
 	}
+	return transactionLogMap(transferLog.GetTransactionLogs()), nil
+}
 
-	for _, a := range transferLog.GetTransactionLogs().GetLogs() {
+// transactionLogMap returns the transactions of the logs keyed by action hash.
+func transactionLogMap(logs *iotextypes.TransactionLogs) map[string][]*iotextypes.TransactionLog_Transaction {
+	transferLogMap := make(map[string][]*iotextypes.TransactionLog_Transaction)
+	for _, a := range logs.GetLogs() {
 		h := hex.EncodeToString(a.ActionHash)
 		transferLogMap[h] = a.GetTransactions()
 	}
-	return transferLogMap, nil
+	return transferLogMap
 }
 
 func getCaller(act *iotextypes.Action) (callerAddr address.Address, err error) {
@@ -65,3 +78,55 @@ func getCaller(act *iotextypes.Action) (callerAddr address.Address, err error) {
 	callerAddr, err = address.FromBytes(srcPub.Hash())
 	return
 }
+
+// actionHash returns the hex encoded hash of the action, the same one the
+// node of the EVM network ID uses to index it.
+func actionHash(act *iotextypes.Action, evmNetworkID uint32) (string, error) {
+	var selp action.SealedEnvelope
+	if err := selp.LoadProto(act); err != nil {
+		return "", err
+	}
+	var (
+		h   hash.Hash256
+		err error
+	)
+	if act.GetEncoding() == iotextypes.Encoding_ETHEREUM_RLP {
+		// SealedEnvelope hashes with the process wide EVM network ID, which
+		// is wrong for all but one of the served networks
+		h, err = rlpSignedHash(&selp, evmNetworkID)
+	} else {
+		h, err = selp.Hash()
+	}
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h[:]), nil
+}
+
+// rlpSignedHash is the hash of the signed Ethereum transaction of the action.
+func rlpSignedHash(selp *action.SealedEnvelope, evmNetworkID uint32) (hash.Hash256, error) {
+	act, ok := selp.Action().(action.EthCompatibleAction)
+	if !ok {
+		return hash.ZeroHash256, action.ErrInvalidAct
+	}
+	tx, err := act.ToEthTx()
+	if err != nil {
+		return hash.ZeroHash256, err
+	}
+	sig := selp.Signature()
+	if len(sig) != 65 {
+		return hash.ZeroHash256, errors.Errorf("invalid signature length = %d, expecting 65", len(sig))
+	}
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	signedTx, err := tx.WithSignature(ethtypes.NewEIP155Signer(new(big.Int).SetUint64(uint64(evmNetworkID))), sig)
+	if err != nil {
+		return hash.ZeroHash256, err
+	}
+	hasher := sha3.NewLegacyKeccak256()
+	if err := rlp.Encode(hasher, signedTx); err != nil {
+		return hash.ZeroHash256, err
+	}
+	return hash.BytesToHash256(hasher.Sum(nil)), nil
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2020 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package iotex_client
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+
+	"github.com/iotexproject/iotex-core-rosetta-gateway/config"
+)
+
+const (
+	// Xrc20TransferType is the operation type of XRC20 token transfers.
+	Xrc20TransferType = "XRC20_TRANSFER"
+	// ContractKey is the name of the key in the Metadata map inside a
+	// Currency that specifies the XRC20 contract address.
+	ContractKey = "contract"
+)
+
+var (
+	// keccak256("Transfer(address,address,uint256)")
+	xrc20TransferTopic, _ = hex.DecodeString("ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	// first 4 bytes of keccak256("transfer(address,uint256)")
+	xrc20TransferMethod, _ = hex.DecodeString("a9059cbb")
+	// first 4 bytes of keccak256("balanceOf(address)")
+	xrc20BalanceOfMethod, _ = hex.DecodeString("70a08231")
+
+	zeroAddress, _ = address.FromBytes(make([]byte, 20))
+)
+
+// TokenCurrency returns the Rosetta currency of the XRC20 token.
+func TokenCurrency(token config.Token) *types.Currency {
+	return &types.Currency{
+		Symbol:   token.Symbol,
+		Decimals: token.Decimals,
+		Metadata: map[string]interface{}{ContractKey: token.Contract},
+	}
+}
+
+// Xrc20TransferData returns the calldata of transfer(recipient, amount).
+func Xrc20TransferData(recipient string, amount *big.Int) ([]byte, error) {
+	addr, err := address.FromString(recipient)
+	if err != nil {
+		return nil, err
+	}
+	if amount.Sign() < 0 || amount.BitLen() > 256 {
+		return nil, errors.Errorf("invalid amount %s", amount)
+	}
+	data := make([]byte, 0, 4+32*2)
+	data = append(data, xrc20TransferMethod...)
+	data = append(data, abiWord(addr.Bytes())...)
+	return append(data, abiWord(amount.Bytes())...), nil
+}
+
+// ParseXrc20TransferData decodes the calldata of transfer(recipient, amount),
+// ok is false if the data is not such a call.
+func ParseXrc20TransferData(data []byte) (recipient string, amount *big.Int, ok bool) {
+	if len(data) != 4+32*2 || !bytes.Equal(data[:4], xrc20TransferMethod) {
+		return "", nil, false
+	}
+	addr, err := address.FromBytes(data[4+12 : 4+32])
+	if err != nil {
+		return "", nil, false
+	}
+	return addr.String(), new(big.Int).SetBytes(data[4+32:]), true
+}
+
+func xrc20BalanceOfData(owner string) ([]byte, error) {
+	addr, err := address.FromString(owner)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, xrc20BalanceOfMethod...), abiWord(addr.Bytes())...), nil
+}
+
+// parseXrc20TransferLog decodes a Transfer(from, to, value) event log.
+func parseXrc20TransferLog(log *iotextypes.Log) (from, to address.Address, amount *big.Int, ok bool) {
+	topics := log.GetTopics()
+	// XRC721 Transfer has the same signature but an indexed third topic
+	if len(topics) != 3 || !bytes.Equal(topics[0], xrc20TransferTopic) ||
+		len(topics[1]) != 32 || len(topics[2]) != 32 {
+		return nil, nil, nil, false
+	}
+	from, err := address.FromBytes(topics[1][12:])
+	if err != nil {
+		return nil, nil, nil, false
+	}
+	to, err = address.FromBytes(topics[2][12:])
+	if err != nil {
+		return nil, nil, nil, false
+	}
+	return from, to, new(big.Int).SetBytes(log.GetData()), true
+}
+
+// abiWord left pads b to a 32 bytes ABI word.
+func abiWord(b []byte) []byte {
+	word := make([]byte, 32)
+	copy(word[32-len(b):], b)
+	return word
+}
@@ -7,9 +7,13 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/coinbase/rosetta-sdk-go/asserter"
 	"github.com/coinbase/rosetta-sdk-go/server"
@@ -17,25 +21,37 @@ import (
 	icconfig "github.com/iotexproject/iotex-core/config"
 
 	"github.com/iotexproject/iotex-core-rosetta-gateway/config"
+	"github.com/iotexproject/iotex-core-rosetta-gateway/indexer"
 	ic "github.com/iotexproject/iotex-core-rosetta-gateway/iotex-client"
 	"github.com/iotexproject/iotex-core-rosetta-gateway/services"
 )
 
 const (
 	ConfigPath = "ConfigPath"
+	// Mode overrides the mode of the config, online or offline
+	Mode = "Mode"
+
+	// shutdownTimeout bounds the wait for the requests in flight on exit
+	shutdownTimeout = 10 * time.Second
 )
 
 // NewBlockchainRouter returns a Mux http.Handler from a collection of
-// Rosetta service controllers.
-func NewBlockchainRouter(client ic.IoTexClient) (http.Handler, error) {
+// Rosetta service controllers, serving the networks of the clients, the
+// transaction search of the indexed ones and the block events of the tracked
+// ones.
+func NewBlockchainRouter(clients []ic.IoTexClient, indexers map[string]*indexer.Indexer, trackers map[string]*indexer.EventTracker) (http.Handler, error) {
+	networks := make([]*types.NetworkIdentifier, 0, len(clients))
+	for _, client := range clients {
+		networks = append(networks, &types.NetworkIdentifier{
+			Blockchain: client.GetConfig().NetworkIdentifier.Blockchain,
+			Network:    client.GetConfig().NetworkIdentifier.Network,
+		})
+	}
+	// the balances at a block are looked up by the account service, which
+	// fails if the node cannot serve them
 	asserter, err := asserter.NewServer(services.SupportedOperationTypes(),
-		false,
-		[]*types.NetworkIdentifier{
-			&types.NetworkIdentifier{
-				Blockchain: client.GetConfig().NetworkIdentifier.Blockchain,
-				Network:    client.GetConfig().NetworkIdentifier.Network,
-			},
-		},
+		true,
+		networks,
 		[]string{},
 		false,
 		"",
@@ -43,12 +59,15 @@ func NewBlockchainRouter(client ic.IoTexClient) (http.Handler, error) {
 	if err != nil {
 		return nil, err
 	}
-	networkAPIController := server.NewNetworkAPIController(services.NewNetworkAPIService(client), asserter)
-	accountAPIController := server.NewAccountAPIController(services.NewAccountAPIService(client), asserter)
-	blockAPIController := server.NewBlockAPIController(services.NewBlockAPIService(client), asserter)
-	constructionAPIController := server.NewConstructionAPIController(services.NewConstructionAPIService(client), asserter)
-	r := server.NewRouter(networkAPIController, accountAPIController, blockAPIController, constructionAPIController)
-	return server.CorsMiddleware(server.LoggerMiddleware(r)), nil
+	networkAPIController := server.NewNetworkAPIController(services.NewNetworkAPIService(clients...), asserter)
+	accountAPIController := server.NewAccountAPIController(services.NewAccountAPIService(clients...), asserter)
+	blockAPIController := server.NewBlockAPIController(services.NewBlockAPIService(clients...), asserter)
+	constructionAPIController := server.NewConstructionAPIController(services.NewConstructionAPIService(clients...), asserter)
+	mempoolAPIController := server.NewMempoolAPIController(services.NewMemPoolAPIService(clients...), asserter)
+	searchAPIController := server.NewSearchAPIController(services.NewSearchAPIService(indexers, clients...), asserter)
+	eventsAPIController := server.NewEventsAPIController(services.NewEventsAPIService(trackers, clients...), asserter)
+	r := server.NewRouter(networkAPIController, accountAPIController, blockAPIController, constructionAPIController, mempoolAPIController, searchAPIController, eventsAPIController)
+	return MetricsMiddleware(HealthMiddleware(clients, server.CorsMiddleware(server.LoggerMiddleware(r)))), nil
 }
 
 func main() {
@@ -60,20 +79,89 @@ func main() {
 	if err != nil {
 		log.Fatalf("ERROR: Failed to parse config: %v\n", err)
 	}
-	icconfig.SetEVMNetworkID(cfg.NetworkIdentifier.EvmNetworkID)
-	// Prepare a new gRPC client.
-	client, err := ic.NewIoTexClient(cfg)
-	if err != nil {
-		log.Fatalf("ERROR: Failed to prepare IoTex gRPC client: %v\n", err)
+	if mode := os.Getenv(Mode); mode != "" {
+		cfg.Server.Mode = mode
+	}
+	switch cfg.Server.Mode {
+	case "", config.OnlineMode, config.OfflineMode:
+	default:
+		log.Fatalf("ERROR: Unknown mode %q\n", cfg.Server.Mode)
+	}
+	networks := cfg.NetworkConfigs()
+	if len(networks) == 0 {
+		log.Fatalf("ERROR: No network is configured\n")
+	}
+	// the process wide EVM network ID is only used to load the RLP actions,
+	// their hashes use the ID of their own network
+	icconfig.SetEVMNetworkID(networks[0].NetworkIdentifier.EvmNetworkID)
+	// the clients and the background routines stop on SIGINT and SIGTERM,
+	// once the server is shut down
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Prepare a gRPC client for each network.
+	clients := make([]ic.IoTexClient, 0, len(networks))
+	for _, network := range networks {
+		client, err := ic.NewIoTexClient(network)
+		if err != nil {
+			log.Fatalf("ERROR: Failed to prepare IoTex gRPC client of %s: %v\n", network.NetworkIdentifier.Network, err)
+		}
+		clients = append(clients, client)
+	}
+	// Track the blocks of the online networks and index the transactions of
+	// the ones with a data directory.
+	indexers := make(map[string]*indexer.Indexer)
+	trackers := make(map[string]*indexer.EventTracker)
+	for _, client := range clients {
+		network := client.GetConfig()
+		if network.Server.Offline() {
+			continue
+		}
+		tracker := indexer.NewEventTracker(client)
+		go tracker.Run(ctx)
+		trackers[network.NetworkIdentifier.Network] = tracker
+		if network.Index.DataDir == "" {
+			continue
+		}
+		idx, err := indexer.New(client)
+		if err != nil {
+			log.Fatalf("ERROR: Failed to open the index of %s: %v\n", network.NetworkIdentifier.Network, err)
+		}
+		go idx.Run(ctx)
+		indexers[network.NetworkIdentifier.Network] = idx
 	}
 
 	// Start the server.
-	router, err := NewBlockchainRouter(client)
+	router, err := NewBlockchainRouter(clients, indexers, trackers)
 	if err != nil {
 		log.Fatalf("ERROR: Failed to init router: %v\n", err)
 	}
-	log.Println("listen", "0.0.0.0:"+cfg.Server.Port)
-	if err := http.ListenAndServe("0.0.0.0:"+cfg.Server.Port, router); err != nil {
+	if cfg.Server.Offline() {
+		log.Println("offline mode, only the offline construction endpoints are served")
+	}
+	server := &http.Server{Addr: "0.0.0.0:" + cfg.Server.Port, Handler: router}
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		<-ctx.Done()
+		log.Println("shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("failed to shut down the server: %v\n", err)
+		}
+	}()
+	log.Println("listen", server.Addr)
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
 		log.Fatalf("IoTex Rosetta Gateway server exited with error: %v\n", err)
 	}
+	// the server returns as soon as it stops listening, the requests in
+	// flight are done once Shutdown returns
+	<-shutdownDone
+	for _, idx := range indexers {
+		idx.Close()
+	}
+	for _, client := range clients {
+		client.Close()
+	}
 }
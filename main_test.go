@@ -0,0 +1,92 @@
+// Copyright (c) 2020 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core-rosetta-gateway/config"
+	ic "github.com/iotexproject/iotex-core-rosetta-gateway/iotex-client"
+	"github.com/iotexproject/iotex-core-rosetta-gateway/iotex-client/mock_client"
+	"github.com/iotexproject/iotex-core-rosetta-gateway/services"
+)
+
+func testConfig() *config.Config {
+	return &config.Config{
+		NetworkIdentifier: config.NetworkIdentifier{
+			Blockchain: "IoTeX",
+			Network:    "testnet",
+		},
+		Currency: config.Currency{
+			Symbol:   "IOTX",
+			Decimals: 18,
+		},
+		Server: config.Server{
+			Port:           "8080",
+			RosettaVersion: "1.4.10",
+		},
+	}
+}
+
+// serve posts the body to the path of the handler.
+func serve(handler http.Handler, path, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+	return rec
+}
+
+func TestBlockchainRouter_AccountBalanceAtBlock(t *testing.T) {
+	var (
+		require = require.New(t)
+		ctrl    = gomock.NewController(t)
+		cli     = mock_client.NewMockIoTexClient(ctrl)
+		tip     = &types.BlockIdentifier{Index: 10, Hash: "block10"}
+		request = func(index int64) string {
+			return `{"network_identifier":{"blockchain":"IoTeX","network":"testnet"},` +
+				`"account_identifier":{"address":"io1d4c5lp4ea4754wy439g2t99ue7wryu5r2lslh2"},` +
+				`"block_identifier":{"index":` + strconv.FormatInt(index, 10) + `}}`
+		}
+	)
+	cli.EXPECT().GetConfig().Return(testConfig()).AnyTimes()
+	cli.EXPECT().GetAccount(gomock.Any(), gomock.Eq(tip.Index), gomock.Any(), gomock.Any()).
+		Return(&types.AccountBalanceResponse{
+			BlockIdentifier: tip,
+			Balances: []*types.Amount{{
+				Value:    "100",
+				Currency: &types.Currency{Symbol: "IOTX", Decimals: 18},
+			}},
+		}, nil).
+		Times(1)
+	cli.EXPECT().GetAccount(gomock.Any(), gomock.Eq(int64(9)), gomock.Any(), gomock.Any()).
+		Return(nil, ic.ErrHistoricalStateUnavailable).
+		Times(1)
+	router, err := NewBlockchainRouter([]ic.IoTexClient{cli}, nil, nil)
+	require.NoError(err)
+
+	// the balance at the tip is served
+	rec := serve(router, "/account/balance", request(tip.Index))
+	require.Equal(http.StatusOK, rec.Code, rec.Body.String())
+	resp := &types.AccountBalanceResponse{}
+	require.NoError(json.Unmarshal(rec.Body.Bytes(), resp))
+	require.Equal(tip, resp.BlockIdentifier)
+
+	// the balance at an older block reaches the account service
+	rec = serve(router, "/account/balance", request(9))
+	require.Equal(http.StatusInternalServerError, rec.Code)
+	served := &types.Error{}
+	require.NoError(json.Unmarshal(rec.Body.Bytes(), served))
+	require.Equal(services.ErrHistoricalBalanceUnavailable.Code, served.Code)
+}
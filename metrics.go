@@ -0,0 +1,101 @@
+// Copyright (c) 2020 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const MetricsPath = "/metrics"
+
+var (
+	requestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "iotex_rosetta_request_duration_seconds",
+			Help:    "Latency of the Rosetta API requests.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"endpoint"},
+	)
+	requests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "iotex_rosetta_requests_total",
+			Help: "Rosetta API requests by HTTP status.",
+		},
+		[]string{"endpoint", "status"},
+	)
+	requestErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "iotex_rosetta_request_errors_total",
+			Help: "Rosetta API errors by error code.",
+		},
+		[]string{"endpoint", "code", "retriable"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(requestDuration, requests, requestErrors)
+}
+
+// statusRecorder keeps the status of the response, and its body if the
+// request failed so that the Rosetta error can be read from it.
+type statusRecorder struct {
+	http.ResponseWriter
+
+	status int
+	body   bytes.Buffer
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status != http.StatusOK {
+		r.body.Write(b)
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// MetricsMiddleware serves the metrics on MetricsPath and records the
+// requests of the other endpoints.
+func MetricsMiddleware(inner http.Handler) http.Handler {
+	metrics := promhttp.Handler()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == MetricsPath {
+			metrics.ServeHTTP(w, r)
+			return
+		}
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		inner.ServeHTTP(rec, r)
+
+		// unknown paths are not labelled one by one
+		endpoint := r.URL.Path
+		if rec.status == http.StatusNotFound || rec.status == http.StatusMethodNotAllowed {
+			endpoint = "unknown"
+		}
+		requestDuration.WithLabelValues(endpoint).Observe(time.Since(start).Seconds())
+		requests.WithLabelValues(endpoint, strconv.Itoa(rec.status)).Inc()
+		if rec.status == http.StatusOK {
+			return
+		}
+		var terr types.Error
+		if err := json.Unmarshal(rec.body.Bytes(), &terr); err == nil {
+			requestErrors.WithLabelValues(endpoint, strconv.Itoa(int(terr.Code)), strconv.FormatBool(terr.Retriable)).Inc()
+		}
+	})
+}
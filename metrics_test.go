@@ -0,0 +1,68 @@
+// Copyright (c) 2020 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	var (
+		require = require.New(t)
+		terr    = &types.Error{Code: 35, Message: "node is unavailable", Retriable: true}
+		mux     = http.NewServeMux()
+	)
+	mux.HandleFunc("/network/list", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	})
+	mux.HandleFunc("/network/status", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(terr)
+	})
+	handler := MetricsMiddleware(mux)
+	count := func(endpoint, status string) float64 {
+		return promtestutil.ToFloat64(requests.WithLabelValues(endpoint, status))
+	}
+	var (
+		listOK      = count("/network/list", "200")
+		statusError = count("/network/status", "500")
+		unknown     = count("unknown", "404")
+		nodeErrors  = promtestutil.ToFloat64(requestErrors.WithLabelValues("/network/status", "35", "true"))
+	)
+
+	// the requests are counted by endpoint and status
+	rec := serve(handler, "/network/list", "{}")
+	require.Equal(http.StatusOK, rec.Code)
+	require.Equal(listOK+1, count("/network/list", "200"))
+
+	// the Rosetta errors by code, and the body still reaches the client
+	rec = serve(handler, "/network/status", "{}")
+	require.Equal(http.StatusInternalServerError, rec.Code)
+	var ret types.Error
+	require.NoError(json.Unmarshal(rec.Body.Bytes(), &ret))
+	require.Equal(*terr, ret)
+	require.Equal(statusError+1, count("/network/status", "500"))
+	require.Equal(nodeErrors+1, promtestutil.ToFloat64(requestErrors.WithLabelValues("/network/status", "35", "true")))
+
+	// the unknown paths share a label
+	rec = serve(handler, "/no/such/path", "{}")
+	require.Equal(http.StatusNotFound, rec.Code)
+	require.Equal(unknown+1, count("unknown", "404"))
+	require.Zero(count("/no/such/path", "404"))
+
+	// the metrics are served but not counted
+	rec = serve(handler, MetricsPath, "")
+	require.Equal(http.StatusOK, rec.Code)
+	require.Contains(rec.Body.String(), "iotex_rosetta_requests_total")
+	require.Zero(count(MetricsPath, "200"))
+}
@@ -11,27 +11,29 @@ import (
 
 	"github.com/coinbase/rosetta-sdk-go/server"
 	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/pkg/errors"
 
 	ic "github.com/iotexproject/iotex-core-rosetta-gateway/iotex-client"
 )
 
 type accountAPIService struct {
-	client ic.IoTexClient
+	clients networkClients
 }
 
 // NewAccountAPIService creates a new instance of an AccountAPIService.
-func NewAccountAPIService(client ic.IoTexClient) server.AccountAPIServicer {
+func NewAccountAPIService(clients ...ic.IoTexClient) server.AccountAPIServicer {
 	return &accountAPIService{
-		client: client,
+		clients: clients,
 	}
 }
 
-// AccountBalance implements the /account/balance endpoint.
+// AccountBalance implements the /account/balance endpoint. The sub-accounts
+// are the unclaimed rewards and the staking buckets, bucket:<index>.
 func (s *accountAPIService) AccountBalance(
 	ctx context.Context,
 	request *types.AccountBalanceRequest,
 ) (*types.AccountBalanceResponse, *types.Error) {
-	terr := ValidateNetworkIdentifier(ctx, s.client, request.NetworkIdentifier)
+	client, terr := s.clients.onlineClient(ctx, request.NetworkIdentifier)
 	if terr != nil {
 		return nil, terr
 	}
@@ -39,17 +41,65 @@ func (s *accountAPIService) AccountBalance(
 	if err != nil {
 		return nil, ErrInvalidAccountAddress
 	}
-	resp, err := s.client.GetAccount(ctx, 0, addr)
+	var height int64
+	if bi := request.BlockIdentifier; bi != nil {
+		if bi.Hash != nil {
+			blk, err := client.GetBlockByHash(ctx, *bi.Hash)
+			if err != nil {
+				return nil, nodeError(ErrUnableToGetBlk, err)
+			}
+			if bi.Index != nil && *bi.Index != blk.BlockIdentifier.Index {
+				return nil, ErrBlockIdentifierMismatch
+			}
+			height = blk.BlockIdentifier.Index
+		} else if bi.Index != nil {
+			height = *bi.Index
+		}
+	}
+	var resp *types.AccountBalanceResponse
+	if sub := request.AccountIdentifier.SubAccount; sub != nil {
+		resp, err = client.GetSubAccount(ctx, height, addr, sub.Address)
+	} else {
+		resp, err = client.GetAccount(ctx, height, addr, request.Currencies)
+	}
 	if err != nil {
-		return nil, ErrUnableToGetAccount
+		switch errors.Cause(err) {
+		case ic.ErrHistoricalStateUnavailable:
+			return nil, ErrHistoricalBalanceUnavailable
+		case ic.ErrUnknownSubAccount:
+			return nil, fieldError(ErrMustSpecifySubAccount, "sub_account", err.Error())
+		}
+		return nil, nodeError(ErrUnableToGetAccount, err)
+	}
+	if bi := request.BlockIdentifier; bi != nil && bi.Hash != nil && *bi.Hash != resp.BlockIdentifier.Hash {
+		return nil, ErrHistoricalBalanceUnavailable
+	}
+	if len(request.Currencies) > 0 {
+		resp.Balances = filterBalances(resp.Balances, request.Currencies)
 	}
 	return resp, nil
 }
 
-// AccountCoins implements the /account/coins endpoint.
+// filterBalances returns the balances of the requested currencies.
+func filterBalances(balances []*types.Amount, currencies []*types.Currency) []*types.Amount {
+	requested := make(map[string]bool, len(currencies))
+	for _, currency := range currencies {
+		requested[types.Hash(currency)] = true
+	}
+	ret := make([]*types.Amount, 0, len(currencies))
+	for _, balance := range balances {
+		if requested[types.Hash(balance.Currency)] {
+			ret = append(ret, balance)
+		}
+	}
+	return ret
+}
+
+// AccountCoins implements the /account/coins endpoint, IoTeX is account based
+// and has no coins.
 func (s *accountAPIService) AccountCoins(
 	context.Context,
 	*types.AccountCoinsRequest,
 ) (*types.AccountCoinsResponse, *types.Error) {
-	return nil, nil
+	return nil, newError(ErrNotImplemented, "/account/coins is not supported by the account based chain")
 }
@@ -2,13 +2,21 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/server"
 	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 
 	"github.com/iotexproject/iotex-core-rosetta-gateway/config"
+	ic "github.com/iotexproject/iotex-core-rosetta-gateway/iotex-client"
 	"github.com/iotexproject/iotex-core-rosetta-gateway/iotex-client/mock_client"
 )
 
@@ -62,7 +70,7 @@ func TestAccountAPIService_AccountBalance(t *testing.T) {
 		cfg     = testConfig()
 	)
 	cli.EXPECT().GetConfig().Return(cfg).AnyTimes()
-	cli.EXPECT().GetAccount(gomock.Any(), gomock.Eq(int64(0)), gomock.Any()).
+	cli.EXPECT().GetAccount(gomock.Any(), gomock.Eq(int64(0)), gomock.Any(), gomock.Any()).
 		Return(ret, nil).
 		AnyTimes()
 
@@ -76,3 +84,234 @@ func TestAccountAPIService_AccountBalance(t *testing.T) {
 	require.Nil(typErr)
 	require.Equal(ret, resp)
 }
+
+func TestAccountAPIService_AccountBalanceAtBlock(t *testing.T) {
+	var (
+		block = &types.BlockIdentifier{
+			Hash:  "block1",
+			Index: 1,
+		}
+		networkIdentifier = &types.NetworkIdentifier{
+			Blockchain: "IoTeX",
+			Network:    "testnet",
+		}
+		ret = &types.AccountBalanceResponse{
+			BlockIdentifier: block,
+			Balances: []*types.Amount{{
+				Value: "100",
+				Currency: &types.Currency{
+					Symbol:   "IOTX",
+					Decimals: 18,
+				},
+			}},
+		}
+		oldIndex  = int64(0)
+		otherHash = "block0"
+
+		require = require.New(t)
+		ctrl    = gomock.NewController(t)
+		cli     = mock_client.NewMockIoTexClient(ctrl)
+		cfg     = testConfig()
+		tests   = []struct {
+			bi  *types.PartialBlockIdentifier
+			err *types.Error
+		}{
+			{&types.PartialBlockIdentifier{Index: &block.Index}, nil},
+			{&types.PartialBlockIdentifier{Hash: &block.Hash}, nil},
+			{&types.PartialBlockIdentifier{Hash: &block.Hash, Index: &oldIndex}, ErrBlockIdentifierMismatch},
+			{&types.PartialBlockIdentifier{Hash: &otherHash}, ErrHistoricalBalanceUnavailable},
+		}
+	)
+	cli.EXPECT().GetConfig().Return(cfg).AnyTimes()
+	cli.EXPECT().GetBlockByHash(gomock.Any(), gomock.Eq(block.Hash)).
+		Return(&types.Block{BlockIdentifier: block}, nil).
+		AnyTimes()
+	cli.EXPECT().GetBlockByHash(gomock.Any(), gomock.Eq(otherHash)).
+		Return(&types.Block{BlockIdentifier: &types.BlockIdentifier{Index: 0, Hash: otherHash}}, nil).
+		AnyTimes()
+	cli.EXPECT().GetAccount(gomock.Any(), gomock.Eq(block.Index), gomock.Any(), gomock.Any()).
+		Return(ret, nil).
+		AnyTimes()
+	cli.EXPECT().GetAccount(gomock.Any(), gomock.Eq(oldIndex), gomock.Any(), gomock.Any()).
+		Return(nil, ic.ErrHistoricalStateUnavailable).
+		AnyTimes()
+
+	clt := NewAccountAPIService(cli)
+	for i, test := range tests {
+		resp, typErr := clt.AccountBalance(context.Background(), &types.AccountBalanceRequest{
+			NetworkIdentifier: networkIdentifier,
+			AccountIdentifier: &types.AccountIdentifier{
+				Address: "io1d4c5lp4ea4754wy439g2t99ue7wryu5r2lslh2",
+			},
+			BlockIdentifier: test.bi,
+		})
+		require.Equal(test.err, typErr, "index: %d", i)
+		if test.err == nil {
+			require.Equal(ret, resp, "index: %d", i)
+		}
+	}
+}
+
+func TestAccountAPIService_AccountBalanceCurrencies(t *testing.T) {
+	var (
+		iotx = &types.Currency{
+			Symbol:   "IOTX",
+			Decimals: 18,
+		}
+		vita = ic.TokenCurrency(config.Token{
+			Contract: "io1hp6y4eqr90j7tmul4w2wa8pm7wx462hq0mg4tw",
+			Symbol:   "VITA",
+			Decimals: 18,
+		})
+		networkIdentifier = &types.NetworkIdentifier{
+			Blockchain: "IoTeX",
+			Network:    "testnet",
+		}
+
+		require = require.New(t)
+		ctrl    = gomock.NewController(t)
+		cli     = mock_client.NewMockIoTexClient(ctrl)
+		cfg     = testConfig()
+	)
+	cli.EXPECT().GetConfig().Return(cfg).AnyTimes()
+	cli.EXPECT().GetAccount(gomock.Any(), gomock.Eq(int64(0)), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(context.Context, int64, string, []*types.Currency) (*types.AccountBalanceResponse, error) {
+			return &types.AccountBalanceResponse{
+				BlockIdentifier: &types.BlockIdentifier{Hash: "block1", Index: 1},
+				Balances: []*types.Amount{
+					{Value: "100", Currency: iotx},
+					{Value: "42", Currency: vita},
+				},
+			}, nil
+		}).
+		AnyTimes()
+
+	clt := NewAccountAPIService(cli)
+	tests := []struct {
+		currencies []*types.Currency
+		expect     []string
+	}{
+		{nil, []string{"100", "42"}},
+		{[]*types.Currency{vita}, []string{"42"}},
+		{[]*types.Currency{iotx, vita}, []string{"100", "42"}},
+		{[]*types.Currency{{Symbol: "VITA", Decimals: 18}}, []string{}},
+	}
+	for i, test := range tests {
+		resp, typErr := clt.AccountBalance(context.Background(), &types.AccountBalanceRequest{
+			NetworkIdentifier: networkIdentifier,
+			AccountIdentifier: &types.AccountIdentifier{
+				Address: "io1d4c5lp4ea4754wy439g2t99ue7wryu5r2lslh2",
+			},
+			Currencies: test.currencies,
+		})
+		require.Nil(typErr, "index: %d", i)
+		values := []string{}
+		for _, balance := range resp.Balances {
+			values = append(values, balance.Value)
+		}
+		require.Equal(test.expect, values, "index: %d", i)
+	}
+}
+
+func TestAccountAPIService_AccountBalanceSubAccount(t *testing.T) {
+	var (
+		networkIdentifier = &types.NetworkIdentifier{
+			Blockchain: "IoTeX",
+			Network:    "testnet",
+		}
+		owner   = "io1d4c5lp4ea4754wy439g2t99ue7wryu5r2lslh2"
+		require = require.New(t)
+		ctrl    = gomock.NewController(t)
+		cli     = mock_client.NewMockIoTexClient(ctrl)
+		clt     = NewAccountAPIService(cli)
+	)
+	cli.EXPECT().GetConfig().Return(testConfig()).AnyTimes()
+	cli.EXPECT().GetSubAccount(gomock.Any(), gomock.Eq(int64(0)), gomock.Eq(owner), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int64, _, subAccount string) (*types.AccountBalanceResponse, error) {
+			balances := map[string]string{ic.BucketSubAccount(3): "300", ic.RewardsSubAccount: "42"}
+			if _, ok := balances[subAccount]; !ok {
+				return nil, errors.Wrap(ic.ErrUnknownSubAccount, subAccount)
+			}
+			return &types.AccountBalanceResponse{
+				BlockIdentifier: &types.BlockIdentifier{Hash: "block1", Index: 1},
+				Balances: []*types.Amount{{
+					Value:    balances[subAccount],
+					Currency: &types.Currency{Symbol: "IOTX", Decimals: 18},
+				}},
+			}, nil
+		}).
+		Times(3)
+
+	for subAccount, expect := range map[string]string{ic.BucketSubAccount(3): "300", ic.RewardsSubAccount: "42"} {
+		resp, typErr := clt.AccountBalance(context.Background(), &types.AccountBalanceRequest{
+			NetworkIdentifier: networkIdentifier,
+			AccountIdentifier: &types.AccountIdentifier{
+				Address:    owner,
+				SubAccount: &types.SubAccountIdentifier{Address: subAccount},
+			},
+		})
+		require.Nil(typErr, subAccount)
+		require.Equal(expect, resp.Balances[0].Value, subAccount)
+	}
+
+	resp, typErr := clt.AccountBalance(context.Background(), &types.AccountBalanceRequest{
+		NetworkIdentifier: networkIdentifier,
+		AccountIdentifier: &types.AccountIdentifier{
+			Address:    owner,
+			SubAccount: &types.SubAccountIdentifier{Address: "escrow"},
+		},
+	})
+	require.Nil(resp)
+	require.Equal(ErrMustSpecifySubAccount.Code, typErr.Code)
+	require.Equal("sub_account", typErr.Details[fieldKey])
+}
+
+func TestAccountAPIService_AccountCoins(t *testing.T) {
+	var (
+		networkIdentifier = &types.NetworkIdentifier{
+			Blockchain: "IoTeX",
+			Network:    "testnet",
+		}
+		require = require.New(t)
+		ctrl    = gomock.NewController(t)
+		cli     = mock_client.NewMockIoTexClient(ctrl)
+		clt     = NewAccountAPIService(cli)
+	)
+	cli.EXPECT().GetConfig().Return(testConfig()).AnyTimes()
+
+	resp, typErr := clt.AccountCoins(context.Background(), &types.AccountCoinsRequest{
+		NetworkIdentifier: networkIdentifier,
+		AccountIdentifier: &types.AccountIdentifier{
+			Address: "io1d4c5lp4ea4754wy439g2t99ue7wryu5r2lslh2",
+		},
+	})
+	require.Nil(resp)
+	require.Equal(ErrNotImplemented.Code, typErr.Code)
+	require.Equal(ErrNotImplemented.Message, typErr.Message)
+	require.NotEmpty(typErr.Details[causeKey])
+
+	// the server answers with the error instead of an empty body
+	asrt, err := asserter.NewServer(SupportedOperationTypes(), false, []*types.NetworkIdentifier{networkIdentifier}, nil, false, "")
+	require.NoError(err)
+	router := server.NewRouter(server.NewAccountAPIController(clt, asrt))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/account/coins", strings.NewReader(
+		`{"network_identifier":{"blockchain":"IoTeX","network":"testnet"},`+
+			`"account_identifier":{"address":"io1d4c5lp4ea4754wy439g2t99ue7wryu5r2lslh2"},"include_mempool":false}`,
+	)))
+	require.Equal(http.StatusInternalServerError, rec.Code)
+	served := &types.Error{}
+	require.NoError(json.Unmarshal(rec.Body.Bytes(), served))
+	require.Equal(ErrNotImplemented.Code, served.Code)
+
+	// nor coins nor the other unsupported lookups are advertised
+	cli.EXPECT().GetVersion(gomock.Any()).Return(nil, nil).Times(1)
+	options, typErr := NewNetworkAPIService(cli).NetworkOptions(context.Background(), &types.NetworkRequest{
+		NetworkIdentifier: networkIdentifier,
+	})
+	require.Nil(typErr)
+	require.False(options.Allow.MempoolCoins)
+	require.False(options.Allow.HistoricalBalanceLookup)
+	require.Empty(options.Allow.CallMethods)
+	require.Contains(options.Allow.Errors, ErrNotImplemented)
+}
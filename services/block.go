@@ -16,13 +16,13 @@ import (
 )
 
 type blockAPIService struct {
-	client ic.IoTexClient
+	clients networkClients
 }
 
 // NewBlockAPIService creates a new instance of an AccountAPIService.
-func NewBlockAPIService(client ic.IoTexClient) server.BlockAPIServicer {
+func NewBlockAPIService(clients ...ic.IoTexClient) server.BlockAPIServicer {
 	return &blockAPIService{
-		client: client,
+		clients: clients,
 	}
 }
 
@@ -30,27 +30,35 @@ func (s *blockAPIService) Block(
 	ctx context.Context,
 	request *types.BlockRequest,
 ) (*types.BlockResponse, *types.Error) {
-	terr := ValidateNetworkIdentifier(ctx, s.client, request.NetworkIdentifier)
+	client, terr := s.clients.onlineClient(ctx, request.NetworkIdentifier)
 	if terr != nil {
 		return nil, terr
 	}
-	var height int64
-
-	if request.BlockIdentifier != nil {
-		if request.BlockIdentifier.Index != nil {
-			height = *request.BlockIdentifier.Index
-		} else if request.BlockIdentifier.Hash != nil {
-			return nil, ErrMustQueryByIndex
+	var (
+		tblk *types.Block
+		err  error
+	)
+	if bi := request.BlockIdentifier; bi != nil && bi.Hash != nil {
+		tblk, err = client.GetBlockByHash(ctx, *bi.Hash)
+		if err != nil {
+			return nil, nodeError(ErrUnableToGetBlk, err)
+		}
+		if bi.Index != nil && *bi.Index != tblk.BlockIdentifier.Index {
+			return nil, ErrBlockIdentifierMismatch
+		}
+	} else {
+		var height int64
+		if bi != nil && bi.Index != nil {
+			height = *bi.Index
+		}
+		tblk, err = client.GetBlock(ctx, height)
+		if err != nil {
+			return nil, nodeError(ErrUnableToGetBlk, err)
 		}
 	}
-
-	tblk, err := s.client.GetBlock(ctx, height)
-	if err != nil {
-		return nil, ErrUnableToGetBlk
-	}
-	tblk.Transactions, err = s.client.GetTransactions(ctx, height)
+	tblk.Transactions, err = client.GetTransactions(ctx, tblk.BlockIdentifier.Index)
 	if err != nil {
-		return nil, ErrUnableToGetBlk
+		return nil, nodeError(ErrUnableToGetBlk, err)
 	}
 
 	resp := &types.BlockResponse{
@@ -65,14 +73,14 @@ func (s *blockAPIService) BlockTransaction(
 	ctx context.Context,
 	request *types.BlockTransactionRequest,
 ) (*types.BlockTransactionResponse, *types.Error) {
-	terr := ValidateNetworkIdentifier(ctx, s.client, request.NetworkIdentifier)
+	client, terr := s.clients.onlineClient(ctx, request.NetworkIdentifier)
 	if terr != nil {
 		return nil, terr
 	}
 
-	transaction, err := s.client.GetBlockTransaction(ctx, request.TransactionIdentifier.Hash)
+	transaction, err := client.GetBlockTransaction(ctx, request.TransactionIdentifier.Hash)
 	if err != nil {
-		return nil, ErrUnableToGetBlkTx
+		return nil, nodeError(ErrUnableToGetBlkTx, err)
 	}
 
 	return &types.BlockTransactionResponse{
@@ -2,11 +2,14 @@ package services
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/iotexproject/iotex-core-rosetta-gateway/iotex-client/mock_client"
 )
@@ -96,6 +99,65 @@ func TestBlockAPIService_Block(t *testing.T) {
 	require.Equal(ret, resp)
 }
 
+func TestBlockAPIService_BlockByHash(t *testing.T) {
+	var (
+		cfg   = testConfig()
+		txs   = []*types.Transaction{}
+		block = &types.Block{
+			BlockIdentifier: &types.BlockIdentifier{
+				Index: 100,
+				Hash:  "block 100",
+			},
+			ParentBlockIdentifier: &types.BlockIdentifier{
+				Index: 99,
+				Hash:  "block 99",
+			},
+			Timestamp: 1000,
+		}
+		networkIdentifier = &types.NetworkIdentifier{
+			Blockchain: "IoTeX",
+			Network:    "testnet",
+		}
+		otherIndex = int64(99)
+
+		require = require.New(t)
+		ctrl    = gomock.NewController(t)
+		cli     = mock_client.NewMockIoTexClient(ctrl)
+		clt     = NewBlockAPIService(cli)
+		tests   = []struct {
+			bi  *types.PartialBlockIdentifier
+			err *types.Error
+		}{
+			{&types.PartialBlockIdentifier{Hash: &block.BlockIdentifier.Hash}, nil},
+			{&types.PartialBlockIdentifier{Hash: &block.BlockIdentifier.Hash, Index: &block.BlockIdentifier.Index}, nil},
+			{&types.PartialBlockIdentifier{Hash: &block.BlockIdentifier.Hash, Index: &otherIndex}, ErrBlockIdentifierMismatch},
+		}
+	)
+	cli.EXPECT().GetConfig().Return(cfg).AnyTimes()
+	cli.EXPECT().GetBlockByHash(gomock.Any(), gomock.Eq(block.BlockIdentifier.Hash)).
+		DoAndReturn(func(context.Context, string) (*types.Block, error) {
+			blk := *block
+			return &blk, nil
+		}).
+		AnyTimes()
+	cli.EXPECT().GetTransactions(gomock.Any(), gomock.Eq(block.BlockIdentifier.Index)).
+		Return(txs, nil).
+		AnyTimes()
+
+	for i, test := range tests {
+		resp, typErr := clt.Block(context.Background(), &types.BlockRequest{
+			NetworkIdentifier: networkIdentifier,
+			BlockIdentifier:   test.bi,
+		})
+		require.Equal(test.err, typErr, "index: %d", i)
+		if test.err != nil {
+			continue
+		}
+		require.Equal(block.BlockIdentifier, resp.Block.BlockIdentifier, "index: %d", i)
+		require.Equal(txs, resp.Block.Transactions, "index: %d", i)
+	}
+}
+
 func TestBlockAPIService_BlockTransaction(t *testing.T) {
 	var (
 		cfg     = testConfig()
@@ -166,3 +228,41 @@ func TestBlockAPIService_BlockTransaction(t *testing.T) {
 	require.Nil(typErr)
 	require.Equal(tx, resp.Transaction)
 }
+
+func TestBlockAPIService_BlockNodeError(t *testing.T) {
+	var (
+		cfg               = testConfig()
+		networkIdentifier = &types.NetworkIdentifier{
+			Blockchain: "IoTeX",
+			Network:    "testnet",
+		}
+
+		require = require.New(t)
+		ctrl    = gomock.NewController(t)
+		cli     = mock_client.NewMockIoTexClient(ctrl)
+		tests   = []struct {
+			err    error
+			expect *types.Error
+		}{
+			{status.Error(codes.Unavailable, "node is down"), ErrNodeUnavailable},
+			{status.Error(codes.DeadlineExceeded, "node is slow"), ErrNodeUnavailable},
+			{status.Error(codes.InvalidArgument, "height is too large"), ErrUnableToGetBlk},
+			{errors.New("not found"), ErrUnableToGetBlk},
+		}
+	)
+	cli.EXPECT().GetConfig().Return(cfg).AnyTimes()
+	clt := NewBlockAPIService(cli)
+	for i, test := range tests {
+		cli.EXPECT().GetBlock(gomock.Any(), gomock.Any()).Return(nil, test.err).Times(1)
+		_, typErr := clt.Block(context.Background(), &types.BlockRequest{
+			NetworkIdentifier: networkIdentifier,
+			BlockIdentifier:   &types.PartialBlockIdentifier{Index: types.Int64(3)},
+		})
+		require.Equal(test.expect.Code, typErr.Code, "index: %d", i)
+		require.Equal(test.expect.Message, typErr.Message, "index: %d", i)
+		require.Equal(test.expect.Retriable, typErr.Retriable, "index: %d", i)
+		require.Equal(test.err.Error(), typErr.Details[causeKey], "index: %d", i)
+	}
+	require.True(ErrNodeUnavailable.Retriable)
+	require.False(ErrUnableToGetBlk.Retriable)
+}
@@ -36,17 +36,81 @@ func ValidateNetworkIdentifier(ctx context.Context, c ic.IoTexClient, ni *types.
 	return nil
 }
 
+// networkClients are the clients of the served networks.
+type networkClients []ic.IoTexClient
+
+// client returns the client of the network identifier, the error of the first
+// network is returned if none matches.
+func (c networkClients) client(ctx context.Context, ni *types.NetworkIdentifier) (ic.IoTexClient, *types.Error) {
+	var terr *types.Error
+	for i, client := range c {
+		err := ValidateNetworkIdentifier(ctx, client, ni)
+		if err == nil {
+			return client, nil
+		}
+		if i == 0 {
+			terr = err
+		}
+	}
+	if terr == nil {
+		return nil, ErrInvalidNetwork
+	}
+	return nil, terr
+}
+
+// onlineClient returns the client of the network identifier like client,
+// ErrUnavailableOffline if the gateway runs without nodes.
+func (c networkClients) onlineClient(ctx context.Context, ni *types.NetworkIdentifier) (ic.IoTexClient, *types.Error) {
+	client, terr := c.client(ctx, ni)
+	if terr != nil {
+		return nil, terr
+	}
+	if client.GetConfig().Server.Offline() {
+		return nil, ErrUnavailableOffline
+	}
+	return client, nil
+}
+
+// nodeError returns ErrNodeUnavailable if the node call failed transiently,
+// terr otherwise, wrapping err in both cases.
+func nodeError(terr *types.Error, err error) *types.Error {
+	if ic.IsRetriable(err) {
+		return wrapError(ErrNodeUnavailable, err)
+	}
+	return wrapError(terr, err)
+}
+
 func SupportedOperationTypes() []string {
 	opTyps := make([]string, 0, len(iotextypes.TransactionLogType_name))
 	for _, name := range iotextypes.TransactionLogType_name {
 		opTyps = append(opTyps, name)
 	}
-	return opTyps
+	return append(opTyps,
+		ic.Xrc20TransferType,
+		ic.BlockRewardType,
+		ic.EpochRewardType,
+		ic.FoundationBonusType,
+		ic.GenesisAllocationType,
+		StakeUnstakeType,
+		StakeRestakeType,
+		StakeChangeCandidateType,
+		StakeTransferOwnershipType,
+	)
 }
 
 func SupportedConstructionTypes() []string {
 	return []string{
 		iotextypes.TransactionLogType_NATIVE_TRANSFER.String(),
+		ic.Xrc20TransferType,
+		iotextypes.TransactionLogType_DEPOSIT_TO_REWARDING_FUND.String(),
+		iotextypes.TransactionLogType_CLAIM_FROM_REWARDING_FUND.String(),
+		iotextypes.TransactionLogType_CREATE_BUCKET.String(),
+		iotextypes.TransactionLogType_DEPOSIT_TO_BUCKET.String(),
+		iotextypes.TransactionLogType_WITHDRAW_BUCKET.String(),
+		StakeUnstakeType,
+		StakeRestakeType,
+		StakeChangeCandidateType,
+		StakeTransferOwnershipType,
 	}
 }
 
@@ -7,6 +7,8 @@ import (
 	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/iotexproject/iotex-proto/golang/iotextypes"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/iotexproject/iotex-core-rosetta-gateway/config"
 	ic "github.com/iotexproject/iotex-core-rosetta-gateway/iotex-client"
@@ -87,6 +89,9 @@ func TestIsSupportedConstructionType(t *testing.T) {
 		expect bool
 	}{
 		{iotextypes.TransactionLogType_NATIVE_TRANSFER.String(), true},
+		{iotextypes.TransactionLogType_CLAIM_FROM_REWARDING_FUND.String(), true},
+		{iotextypes.TransactionLogType_CREATE_BUCKET.String(), true},
+		{StakeTransferOwnershipType, true},
 		{"OTHERS", false},
 	}
 	for i, test := range tests {
@@ -94,3 +99,22 @@ func TestIsSupportedConstructionType(t *testing.T) {
 		require.Equal(test.expect, isSupported, "index:", i)
 	}
 }
+
+func TestErrorDetails(t *testing.T) {
+	require := require.New(t)
+
+	typErr := wrapError(ErrInvalidInputParam, status.Error(codes.InvalidArgument, "bad nonce"))
+	require.Equal(ErrInvalidInputParam.Code, typErr.Code)
+	require.Equal(ErrInvalidInputParam.Message, typErr.Message)
+	require.Equal(status.Error(codes.InvalidArgument, "bad nonce").Error(), typErr.Details[causeKey])
+	require.Equal(codes.InvalidArgument.String(), typErr.Details[nodeCodeKey])
+
+	typErr = fieldError(ErrInvalidInputParam, "amount", "empty amount")
+	require.Equal("amount", typErr.Details[fieldKey])
+	require.Equal("empty amount", typErr.Details[causeKey])
+	require.NotContains(typErr.Details, nodeCodeKey)
+
+	// the canonical errors are never changed
+	require.Equal("invalid input param", ErrInvalidInputParam.Message)
+	require.Nil(ErrInvalidInputParam.Details)
+}
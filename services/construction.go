@@ -15,6 +15,7 @@ import (
 	"github.com/coinbase/rosetta-sdk-go/server"
 	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
 	"github.com/spf13/cast"
 
 	"github.com/iotexproject/go-pkgs/crypto"
@@ -23,6 +24,7 @@ import (
 	"github.com/iotexproject/iotex-core/action"
 	"github.com/iotexproject/iotex-proto/golang/iotextypes"
 
+	"github.com/iotexproject/iotex-core-rosetta-gateway/config"
 	ic "github.com/iotexproject/iotex-core-rosetta-gateway/iotex-client"
 )
 
@@ -33,13 +35,13 @@ const (
 )
 
 type constructionAPIService struct {
-	client ic.IoTexClient
+	clients networkClients
 }
 
 // NewConstructionAPIService creates a new instance of an ConstructionAPIService.
-func NewConstructionAPIService(client ic.IoTexClient) server.ConstructionAPIServicer {
+func NewConstructionAPIService(clients ...ic.IoTexClient) server.ConstructionAPIServicer {
 	return &constructionAPIService{
-		client: client,
+		clients: clients,
 	}
 }
 
@@ -48,15 +50,13 @@ func (s *constructionAPIService) ConstructionCombine(
 	ctx context.Context,
 	request *types.ConstructionCombineRequest,
 ) (*types.ConstructionCombineResponse, *types.Error) {
-	if terr := ValidateNetworkIdentifier(ctx, s.client, request.NetworkIdentifier); terr != nil {
+	if _, terr := s.clients.client(ctx, request.NetworkIdentifier); terr != nil {
 		return nil, terr
 	}
 
 	tran, err := hex.DecodeString(request.UnsignedTransaction)
 	if err != nil {
-		terr := ErrInvalidInputParam
-		terr.Message += err.Error()
-		return nil, terr
+		return nil, wrapError(ErrInvalidInputParam, err)
 	}
 	act := &iotextypes.Action{}
 	if err := proto.Unmarshal(tran, act); err != nil {
@@ -64,18 +64,14 @@ func (s *constructionAPIService) ConstructionCombine(
 	}
 
 	if len(request.Signatures) != 1 {
-		terr := ErrInvalidInputParam
-		terr.Message += "need exact 1 signature"
-		return nil, terr
+		return nil, newError(ErrInvalidInputParam, "need exact 1 signature")
 	}
 
 	rawPub := request.Signatures[0].PublicKey.Bytes
 	if btcec.IsCompressedPubKey(rawPub) {
 		pubk, err := btcec.ParsePubKey(rawPub, btcec.S256())
 		if err != nil {
-			terr := ErrInvalidInputParam
-			terr.Message += "invalid pubkey: " + err.Error()
-			return nil, terr
+			return nil, wrapError(ErrInvalidInputParam, errors.Wrap(err, "invalid pubkey"))
 		}
 		rawPub = pubk.SerializeUncompressed()
 	}
@@ -84,17 +80,13 @@ func (s *constructionAPIService) ConstructionCombine(
 
 	rawSig := request.Signatures[0].Bytes
 	if len(rawSig) != 65 {
-		terr := ErrInvalidInputParam
-		terr.Message += "invalid signature length"
-		return nil, terr
+		return nil, newError(ErrInvalidInputParam, "invalid signature length")
 	}
 	act.Signature = rawSig
 
 	msg, err := proto.Marshal(act)
 	if err != nil {
-		terr := ErrServiceInternal
-		terr.Message += err.Error()
-		return nil, terr
+		return nil, wrapError(ErrServiceInternal, err)
 	}
 	return &types.ConstructionCombineResponse{
 		SignedTransaction: hex.EncodeToString(msg),
@@ -106,38 +98,30 @@ func (s *constructionAPIService) ConstructionDerive(
 	ctx context.Context,
 	request *types.ConstructionDeriveRequest,
 ) (*types.ConstructionDeriveResponse, *types.Error) {
-	if terr := ValidateNetworkIdentifier(ctx, s.client, request.NetworkIdentifier); terr != nil {
+	if _, terr := s.clients.client(ctx, request.NetworkIdentifier); terr != nil {
 		return nil, terr
 	}
 
 	if len(request.PublicKey.Bytes) == 0 || request.PublicKey.CurveType != CurveType {
-		terr := ErrInvalidInputParam
-		terr.Message += "unsupported public key type"
-		return nil, terr
+		return nil, newError(ErrInvalidInputParam, "unsupported public key type")
 	}
 
 	rawPub := request.PublicKey.Bytes
 	if btcec.IsCompressedPubKey(rawPub) {
 		pubk, err := btcec.ParsePubKey(rawPub, btcec.S256())
 		if err != nil {
-			terr := ErrInvalidInputParam
-			terr.Message += "invalid public key: " + err.Error()
-			return nil, terr
+			return nil, wrapError(ErrInvalidInputParam, errors.Wrap(err, "invalid public key"))
 		}
 		rawPub = pubk.SerializeUncompressed()
 	}
 
 	pub, err := crypto.BytesToPublicKey(rawPub)
 	if err != nil {
-		terr := ErrInvalidInputParam
-		terr.Message += "invalid public key: " + err.Error()
-		return nil, terr
+		return nil, wrapError(ErrInvalidInputParam, errors.Wrap(err, "invalid public key"))
 	}
 	addr, err := address.FromBytes(pub.Hash())
 	if err != nil {
-		terr := ErrInvalidInputParam
-		terr.Message += "invalid public key: " + err.Error()
-		return nil, terr
+		return nil, wrapError(ErrInvalidInputParam, errors.Wrap(err, "invalid public key"))
 	}
 	return &types.ConstructionDeriveResponse{
 		AccountIdentifier: &types.AccountIdentifier{
@@ -151,14 +135,12 @@ func (s *constructionAPIService) ConstructionHash(
 	ctx context.Context,
 	request *types.ConstructionHashRequest,
 ) (*types.TransactionIdentifierResponse, *types.Error) {
-	if terr := ValidateNetworkIdentifier(ctx, s.client, request.NetworkIdentifier); terr != nil {
+	if _, terr := s.clients.client(ctx, request.NetworkIdentifier); terr != nil {
 		return nil, terr
 	}
 	tran, err := hex.DecodeString(request.SignedTransaction)
 	if err != nil {
-		terr := ErrInvalidInputParam
-		terr.Message += "invalid signed transaction format: " + err.Error()
-		return nil, terr
+		return nil, wrapError(ErrInvalidInputParam, errors.Wrap(err, "invalid signed transaction format"))
 	}
 	h := hash.Hash256b(tran)
 
@@ -175,50 +157,54 @@ type metadataInputOptions struct {
 	gasPrice      *uint64
 	maxFee        *big.Int
 	feeMultiplier *float64
-	typ           iotextypes.TransactionLogType
+	typ           string
+	// only used to estimate the gas of XRC20 transfers
+	recipient string
+	amount    string
+	contract  string
 }
 
 func parseMetadataInputOptions(options map[string]interface{}) (*metadataInputOptions, *types.Error) {
 	opts := &metadataInputOptions{}
 	idRaw, ok := options["sender"]
 	if !ok {
-		terr := ErrInvalidInputParam
-		terr.Message += "empty sender address"
-		return nil, terr
+		return nil, fieldError(ErrInvalidInputParam, "sender", "empty sender address")
 	}
 
 	var err error
 	opts.senderAddress, err = cast.ToStringE(idRaw)
 	if err != nil {
-		terr := ErrInvalidInputParam
-		terr.Message += err.Error()
-		return nil, terr
+		return nil, fieldError(ErrInvalidInputParam, "sender", err.Error())
 	}
 
 	if _, ok := options["type"]; !ok {
-		terr := ErrInvalidInputParam
-		terr.Message += "empty operation type"
-		return nil, terr
+		return nil, fieldError(ErrInvalidInputParam, "type", "empty operation type")
 	}
 	typ, err := cast.ToStringE(options["type"])
 	if err != nil {
-		terr := ErrInvalidInputParam
-		terr.Message += "failed to parse type: " + err.Error()
-		return nil, terr
+		return nil, fieldError(ErrInvalidInputParam, "type", errors.Wrap(err, "failed to parse type").Error())
 	}
 	if !IsSupportedConstructionType(typ) {
-		terr := ErrInvalidInputParam
-		terr.Message += "unsupported type"
-		return nil, terr
+		return nil, fieldError(ErrInvalidInputParam, "type", "unsupported type")
+	}
+	opts.typ = typ
+
+	if typ == ic.Xrc20TransferType {
+		for key, field := range map[string]*string{
+			"recipient": &opts.recipient,
+			"amount":    &opts.amount,
+			"contract":  &opts.contract,
+		} {
+			if *field, err = cast.ToStringE(options[key]); err != nil || *field == "" {
+				return nil, fieldError(ErrInvalidInputParam, key, "empty "+key)
+			}
+		}
 	}
-	opts.typ = iotextypes.TransactionLogType(iotextypes.TransactionLogType_value[typ])
 
 	if rawgl, ok := options["gasLimit"]; ok {
 		gasLimit, err := cast.ToUint64E(rawgl)
 		if err != nil {
-			terr := ErrInvalidInputParam
-			terr.Message += "failed to parse gasLimit: " + err.Error()
-			return nil, terr
+			return nil, fieldError(ErrInvalidInputParam, "gasLimit", errors.Wrap(err, "failed to parse gasLimit").Error())
 		}
 		opts.gasLimit = &gasLimit
 	}
@@ -226,9 +212,7 @@ func parseMetadataInputOptions(options map[string]interface{}) (*metadataInputOp
 	if rawgp, ok := options["gasPrice"]; ok {
 		gasPrice, err := cast.ToUint64E(rawgp)
 		if err != nil {
-			terr := ErrInvalidInputParam
-			terr.Message += "failed to parse gasPrice: " + err.Error()
-			return nil, terr
+			return nil, fieldError(ErrInvalidInputParam, "gasPrice", errors.Wrap(err, "failed to parse gasPrice").Error())
 		}
 		opts.gasPrice = &gasPrice
 	}
@@ -236,9 +220,7 @@ func parseMetadataInputOptions(options map[string]interface{}) (*metadataInputOp
 	if rawmp, ok := options["feeMultiplier"]; ok {
 		feeMultiplier, err := cast.ToFloat64E(rawmp)
 		if err != nil {
-			terr := ErrInvalidInputParam
-			terr.Message += "failed to parse fee multiplier: " + err.Error()
-			return nil, terr
+			return nil, fieldError(ErrInvalidInputParam, "feeMultiplier", errors.Wrap(err, "failed to parse fee multiplier").Error())
 		}
 		opts.feeMultiplier = &feeMultiplier
 	}
@@ -246,15 +228,11 @@ func parseMetadataInputOptions(options map[string]interface{}) (*metadataInputOp
 	if rawmf, ok := options["maxFee"]; ok {
 		maxFeeStr, err := cast.ToStringE(rawmf)
 		if err != nil {
-			terr := ErrInvalidInputParam
-			terr.Message += "failed to parse max fee: " + err.Error()
-			return nil, terr
+			return nil, fieldError(ErrInvalidInputParam, "maxFee", errors.Wrap(err, "failed to parse max fee").Error())
 		}
 		maxFee, ok := new(big.Int).SetString(maxFeeStr, 10)
 		if !ok {
-			terr := ErrInvalidInputParam
-			terr.Message += "failed to parse max fee"
-			return nil, terr
+			return nil, fieldError(ErrInvalidInputParam, "maxFee", "failed to parse max fee")
 		}
 		opts.maxFee = maxFee
 	}
@@ -266,9 +244,7 @@ func estimateGasAction(opts *metadataInputOptions) (*iotextypes.Action, *types.E
 	// need a valid pubkey to estimate, just use one
 	rawPrivKey, err := btcec.NewPrivateKey(btcec.S256())
 	if err != nil {
-		terr := ErrServiceInternal
-		terr.Message += err.Error()
-		return nil, terr
+		return nil, wrapError(ErrServiceInternal, err)
 	}
 	rawPubKey := rawPrivKey.PubKey()
 	act := &iotextypes.Action{
@@ -276,13 +252,48 @@ func estimateGasAction(opts *metadataInputOptions) (*iotextypes.Action, *types.E
 		Signature:    action.ValidSig,
 	}
 
+	act.Core = &iotextypes.ActionCore{}
 	switch opts.typ {
 	// XXX once support send out payload, need to pass payload here to get right gaslimit
-	case iotextypes.TransactionLogType_NATIVE_TRANSFER:
-		act.Core = &iotextypes.ActionCore{
-			Action: &iotextypes.ActionCore_Transfer{
-				Transfer: &iotextypes.Transfer{},
-			},
+	case iotextypes.TransactionLogType_NATIVE_TRANSFER.String():
+		act.Core.Action = &iotextypes.ActionCore_Transfer{
+			Transfer: &iotextypes.Transfer{},
+		}
+	case depositToRewardingFundType:
+		act.Core.Action = &iotextypes.ActionCore_DepositToRewardingFund{
+			DepositToRewardingFund: &iotextypes.DepositToRewardingFund{Amount: "0"},
+		}
+	case claimFromRewardingFundType:
+		act.Core.Action = &iotextypes.ActionCore_ClaimFromRewardingFund{
+			ClaimFromRewardingFund: &iotextypes.ClaimFromRewardingFund{Amount: "0"},
+		}
+	case stakeCreateType:
+		act.Core.Action = &iotextypes.ActionCore_StakeCreate{
+			StakeCreate: &iotextypes.StakeCreate{},
+		}
+	case stakeAddDepositType:
+		act.Core.Action = &iotextypes.ActionCore_StakeAddDeposit{
+			StakeAddDeposit: &iotextypes.StakeAddDeposit{},
+		}
+	case StakeUnstakeType:
+		act.Core.Action = &iotextypes.ActionCore_StakeUnstake{
+			StakeUnstake: &iotextypes.StakeReclaim{},
+		}
+	case stakeWithdrawType:
+		act.Core.Action = &iotextypes.ActionCore_StakeWithdraw{
+			StakeWithdraw: &iotextypes.StakeReclaim{},
+		}
+	case StakeRestakeType:
+		act.Core.Action = &iotextypes.ActionCore_StakeRestake{
+			StakeRestake: &iotextypes.StakeRestake{},
+		}
+	case StakeChangeCandidateType:
+		act.Core.Action = &iotextypes.ActionCore_StakeChangeCandidate{
+			StakeChangeCandidate: &iotextypes.StakeChangeCandidate{},
+		}
+	case StakeTransferOwnershipType:
+		act.Core.Action = &iotextypes.ActionCore_StakeTransferOwnership{
+			StakeTransferOwnership: &iotextypes.StakeTransferOwnership{},
 		}
 	}
 	return act, nil
@@ -293,7 +304,8 @@ func (s *constructionAPIService) ConstructionMetadata(
 	ctx context.Context,
 	request *types.ConstructionMetadataRequest,
 ) (*types.ConstructionMetadataResponse, *types.Error) {
-	if terr := ValidateNetworkIdentifier(ctx, s.client, request.NetworkIdentifier); terr != nil {
+	client, terr := s.clients.onlineClient(ctx, request.NetworkIdentifier)
+	if terr != nil {
 		return nil, terr
 	}
 
@@ -301,36 +313,40 @@ func (s *constructionAPIService) ConstructionMetadata(
 	if terr != nil {
 		return nil, terr
 	}
-	account, err := s.client.GetAccount(ctx, 0, opts.senderAddress)
+	account, err := client.GetAccount(ctx, 0, opts.senderAddress, nil)
 	if err != nil {
-		terr := ErrUnableToGetAccount
-		terr.Message += err.Error()
-		return nil, terr
+		return nil, nodeError(ErrUnableToGetAccount, err)
 	}
 	meta := account.Metadata
 
 	var gasLimit, gasPrice uint64
-	if opts.gasLimit == nil {
+	if opts.gasLimit == nil && opts.typ == ic.Xrc20TransferType {
+		execution, err := xrc20TransferExecution(opts.contract, opts.recipient, opts.amount)
+		if err != nil {
+			return nil, wrapError(ErrInvalidInputParam, err)
+		}
+		// a token transfer has to be simulated by the real sender
+		gasLimit, err = client.EstimateExecutionGas(ctx, opts.senderAddress, execution)
+		if err != nil {
+			return nil, nodeError(ErrUnableToEstimateGas, err)
+		}
+	} else if opts.gasLimit == nil {
 		estAct, terr := estimateGasAction(opts)
 		if terr != nil {
 			return nil, terr
 		}
-		gasLimit, err = s.client.EstimateGasForAction(ctx, estAct)
+		gasLimit, err = client.EstimateGasForAction(ctx, estAct)
 		if err != nil {
-			terr := ErrUnableToEstimateGas
-			terr.Message += err.Error()
-			return nil, terr
+			return nil, nodeError(ErrUnableToEstimateGas, err)
 		}
 	} else {
 		gasLimit = *opts.gasLimit
 	}
 
 	if opts.gasPrice == nil {
-		gasPrice, err = s.client.SuggestGasPrice(ctx)
+		gasPrice, err = client.SuggestGasPrice(ctx)
 		if err != nil {
-			terr := ErrUnableToGetSuggestGas
-			terr.Message += err.Error()
-			return nil, terr
+			return nil, nodeError(ErrUnableToGetSuggestGas, err)
 		}
 	} else {
 		gasPrice = *opts.gasPrice
@@ -362,8 +378,8 @@ func (s *constructionAPIService) ConstructionMetadata(
 			&types.Amount{
 				Value: suggestedFee.String(),
 				Currency: &types.Currency{
-					Symbol:   s.client.GetConfig().Currency.Symbol,
-					Decimals: s.client.GetConfig().Currency.Decimals,
+					Symbol:   client.GetConfig().Currency.Symbol,
+					Decimals: client.GetConfig().Currency.Decimals,
 				},
 			},
 		},
@@ -375,7 +391,8 @@ func (s *constructionAPIService) ConstructionParse(
 	ctx context.Context,
 	request *types.ConstructionParseRequest,
 ) (*types.ConstructionParseResponse, *types.Error) {
-	if terr := ValidateNetworkIdentifier(ctx, s.client, request.NetworkIdentifier); terr != nil {
+	client, terr := s.clients.client(ctx, request.NetworkIdentifier)
+	if terr != nil {
 		return nil, terr
 	}
 	tran, err := hex.DecodeString(request.Transaction)
@@ -392,7 +409,7 @@ func (s *constructionAPIService) ConstructionParse(
 	if terr != nil {
 		return nil, terr
 	}
-	ops, meta := s.ioActionToOps(sender, act)
+	ops, meta := s.ioActionToOps(client.GetConfig(), sender, act)
 
 	resp := &types.ConstructionParseResponse{
 		Operations: ops,
@@ -413,27 +430,24 @@ func (s *constructionAPIService) ConstructionPayloads(
 	ctx context.Context,
 	request *types.ConstructionPayloadsRequest,
 ) (*types.ConstructionPayloadsResponse, *types.Error) {
-	if err := ValidateNetworkIdentifier(ctx, s.client, request.NetworkIdentifier); err != nil {
-		return nil, err
+	client, terr := s.clients.client(ctx, request.NetworkIdentifier)
+	if terr != nil {
+		return nil, terr
 	}
-	if err := s.checkOperationAndMeta(request.Operations, request.Metadata, true); err != nil {
+	if err := s.checkOperationAndMeta(client.GetConfig(), request.Operations, request.Metadata, true); err != nil {
 		return nil, err
 	}
 
 	act := s.opsToIoAction(request.Operations, request.Metadata)
 	msg, err := proto.Marshal(act)
 	if err != nil {
-		terr := ErrServiceInternal
-		terr.Message += err.Error()
-		return nil, terr
+		return nil, wrapError(ErrServiceInternal, err)
 	}
 	unsignedTx := hex.EncodeToString(msg)
 
 	core, err := proto.Marshal(act.GetCore())
 	if err != nil {
-		terr := ErrServiceInternal
-		terr.Message += err.Error()
-		return nil, terr
+		return nil, wrapError(ErrServiceInternal, err)
 	}
 	h := hash.Hash256b(core)
 	return &types.ConstructionPayloadsResponse{
@@ -455,21 +469,31 @@ func (s *constructionAPIService) ConstructionPreprocess(
 	ctx context.Context,
 	request *types.ConstructionPreprocessRequest,
 ) (*types.ConstructionPreprocessResponse, *types.Error) {
-	if err := ValidateNetworkIdentifier(ctx, s.client, request.NetworkIdentifier); err != nil {
-		return nil, err
+	client, terr := s.clients.client(ctx, request.NetworkIdentifier)
+	if terr != nil {
+		return nil, terr
 	}
 
-	if err := s.checkOperationAndMeta(request.Operations, request.Metadata, false); err != nil {
+	if err := s.checkOperationAndMeta(client.GetConfig(), request.Operations, request.Metadata, false); err != nil {
 		return nil, err
 	}
 
 	options := make(map[string]interface{})
 	options["sender"] = request.Operations[0].Account.Address
 	options["type"] = request.Operations[0].Type
-	options["amount"] = request.Operations[1].Amount.Value
-	options["symbol"] = request.Operations[1].Amount.Currency.Symbol
-	options["decimals"] = request.Operations[1].Amount.Currency.Decimals
-	options["recipient"] = request.Operations[1].Account.Address
+	// single operation staking actions have no recipient nor amount
+	if len(request.Operations) > 1 {
+		recipient := request.Operations[1]
+		options["recipient"] = recipient.Account.Address
+		if recipient.Amount != nil {
+			options["amount"] = recipient.Amount.Value
+			options["symbol"] = recipient.Amount.Currency.Symbol
+			options["decimals"] = recipient.Amount.Currency.Decimals
+			if contract, ok := recipient.Amount.Currency.Metadata[ic.ContractKey]; ok {
+				options["contract"] = contract
+			}
+		}
+	}
 
 	// XXX it is unclear where these meta data should be
 	if request.Metadata["gasLimit"] != nil {
@@ -482,11 +506,9 @@ func (s *constructionAPIService) ConstructionPreprocess(
 	// check and set max fee and fee multiplier
 	if len(request.MaxFee) != 0 {
 		maxFee := request.MaxFee[0]
-		if maxFee.Currency.Symbol != s.client.GetConfig().Currency.Symbol ||
-			maxFee.Currency.Decimals != s.client.GetConfig().Currency.Decimals {
-			terr := ErrConstructionCheck
-			terr.Message += "invalid currency"
-			return nil, terr
+		if maxFee.Currency.Symbol != client.GetConfig().Currency.Symbol ||
+			maxFee.Currency.Decimals != client.GetConfig().Currency.Decimals {
+			return nil, newError(ErrConstructionCheck, "invalid currency")
 		}
 		options["maxFee"] = maxFee.Value
 	}
@@ -504,29 +526,23 @@ func (s *constructionAPIService) ConstructionSubmit(
 	ctx context.Context,
 	request *types.ConstructionSubmitRequest,
 ) (*types.TransactionIdentifierResponse, *types.Error) {
-	terr := ValidateNetworkIdentifier(ctx, s.client, request.NetworkIdentifier)
+	client, terr := s.clients.onlineClient(ctx, request.NetworkIdentifier)
 	if terr != nil {
 		return nil, terr
 	}
 	tran, err := hex.DecodeString(request.SignedTransaction)
 	if err != nil {
-		terr := ErrInvalidInputParam
-		terr.Message += err.Error()
-		return nil, terr
+		return nil, wrapError(ErrInvalidInputParam, err)
 	}
 
 	act := &iotextypes.Action{}
 	if err := proto.Unmarshal(tran, act); err != nil {
-		terr := ErrInvalidInputParam
-		terr.Message += err.Error()
-		return nil, terr
+		return nil, wrapError(ErrInvalidInputParam, err)
 	}
 
-	txID, err := s.client.SubmitTx(ctx, act)
+	txID, err := client.SubmitTx(ctx, act)
 	if err != nil {
-		terr := ErrUnableToSubmitTx
-		terr.Message += err.Error()
-		return nil, terr
+		return nil, nodeError(ErrUnableToSubmitTx, err)
 	}
 
 	return &types.TransactionIdentifierResponse{
@@ -548,14 +564,22 @@ func (s *constructionAPIService) opsToIoAction(ops []*types.Operation, meta map[
 		SenderPubKey: []byte(ops[0].Account.Address),
 	}
 
-	switch iotextypes.TransactionLogType(iotextypes.TransactionLogType_value[ops[0].Type]) {
-	case iotextypes.TransactionLogType_NATIVE_TRANSFER:
+	switch ops[0].Type {
+	case iotextypes.TransactionLogType_NATIVE_TRANSFER.String():
 		act.Core.Action = &iotextypes.ActionCore_Transfer{Transfer: opsToIoTransfer(ops)}
+	case ic.Xrc20TransferType:
+		act.Core.Action = &iotextypes.ActionCore_Execution{Execution: opsToIoXrc20Transfer(ops)}
+	case depositToRewardingFundType:
+		act.Core.Action = &iotextypes.ActionCore_DepositToRewardingFund{DepositToRewardingFund: opsToIoDepositToRewardingFund(ops)}
+	case claimFromRewardingFundType:
+		act.Core.Action = &iotextypes.ActionCore_ClaimFromRewardingFund{ClaimFromRewardingFund: opsToIoClaimFromRewardingFund(ops)}
+	default:
+		opsToIoStakeAction(act.Core, ops)
 	}
 	return act
 }
 
-func (s *constructionAPIService) ioActionToOps(sender string, act *iotextypes.Action) ([]*types.Operation, map[string]interface{}) {
+func (s *constructionAPIService) ioActionToOps(cfg *config.Config, sender string, act *iotextypes.Action) ([]*types.Operation, map[string]interface{}) {
 	meta := make(map[string]interface{})
 	meta["nonce"] = act.GetCore().GetNonce()
 	meta["gasLimit"] = act.GetCore().GetGasLimit()
@@ -563,74 +587,92 @@ func (s *constructionAPIService) ioActionToOps(sender string, act *iotextypes.Ac
 	meta["gasPrice"] = gasPrice.Uint64()
 
 	actCore := act.GetCore()
+	currency := &types.Currency{
+		Symbol:   cfg.Currency.Symbol,
+		Decimals: cfg.Currency.Decimals,
+	}
 	var ops []*types.Operation
 	switch {
 	case actCore.GetTransfer() != nil:
-		ops = ioTransferToOps(sender, actCore.GetTransfer(), &types.Currency{
-			Symbol:   s.client.GetConfig().Currency.Symbol,
-			Decimals: s.client.GetConfig().Currency.Decimals,
-		})
+		ops = ioTransferToOps(sender, actCore.GetTransfer(), currency)
+	case actCore.GetExecution() != nil:
+		ops = ioXrc20TransferToOps(sender, actCore.GetExecution(), cfg)
+	case actCore.GetDepositToRewardingFund() != nil:
+		ops = ioRewardingToOps(sender, depositToRewardingFundType, actCore.GetDepositToRewardingFund().GetAmount(), currency)
+	case actCore.GetClaimFromRewardingFund() != nil:
+		ops = ioRewardingToOps(sender, claimFromRewardingFundType, actCore.GetClaimFromRewardingFund().GetAmount(), currency)
+	default:
+		ops = ioStakeActionToOps(sender, actCore, currency)
 	}
 	return ops, meta
 }
 
-func (s *constructionAPIService) checkOperationAndMeta(ops []*types.Operation, meta map[string]interface{}, mustMeta bool) *types.Error {
-
-	terr := ErrConstructionCheck
+func (s *constructionAPIService) checkOperationAndMeta(cfg *config.Config, ops []*types.Operation, meta map[string]interface{}, mustMeta bool) *types.Error {
 	if len(ops) == 0 {
-		terr.Message += "operation numbers are no expected"
-		return terr
+		return newError(ErrConstructionCheck, "operation numbers are no expected")
 	}
 	typ := ops[0].Type
 	if !IsSupportedConstructionType(typ) {
-		terr.Message += "unsupported construction type"
-		return terr
-	}
-	switch iotextypes.TransactionLogType(iotextypes.TransactionLogType_value[typ]) {
-	case iotextypes.TransactionLogType_NATIVE_TRANSFER:
-		if terr := checkTransferOps(ops, &types.Currency{
-			Symbol:   s.client.GetConfig().Currency.Symbol,
-			Decimals: s.client.GetConfig().Currency.Decimals,
-		}); terr != nil {
-			return terr
-		}
+		return newError(ErrConstructionCheck, "unsupported construction type")
+	}
+	currency := &types.Currency{
+		Symbol:   cfg.Currency.Symbol,
+		Decimals: cfg.Currency.Decimals,
+	}
+	var opsErr *types.Error
+	switch typ {
+	case iotextypes.TransactionLogType_NATIVE_TRANSFER.String():
+		opsErr = checkTransferOps(ops, currency)
+	case ic.Xrc20TransferType:
+		opsErr = checkXrc20TransferOps(ops, cfg)
+	case depositToRewardingFundType, claimFromRewardingFundType:
+		opsErr = checkRewardingOps(ops, currency)
+	case stakeCreateType:
+		opsErr = checkStakeCreateOps(ops, currency)
+	case stakeAddDepositType:
+		opsErr = checkStakeAddDepositOps(ops, currency)
+	case StakeUnstakeType, stakeWithdrawType:
+		opsErr = checkStakeBucketOps(ops)
+	case StakeRestakeType:
+		opsErr = checkStakeRestakeOps(ops)
+	case StakeChangeCandidateType:
+		opsErr = checkStakeChangeCandidateOps(ops)
+	case StakeTransferOwnershipType:
+		opsErr = checkStakeTransferOwnershipOps(ops)
+	}
+	if opsErr != nil {
+		return opsErr
 	}
 
 	// check metadata exists
 	if mustMeta {
 		if meta["gasLimit"] == nil || meta["gasPrice"] == nil || meta["nonce"] == nil {
-			terr.Message += "metadata not complete"
-			return terr
+			return newError(ErrConstructionCheck, "metadata not complete")
 		}
 	}
 
 	// check gas
 	if meta["gasLimit"] != nil {
 		if _, err := cast.ToUint64E(meta["gasLimit"]); err != nil {
-			terr.Message += "invalid gas limit"
-			return terr
+			return newError(ErrConstructionCheck, "invalid gas limit")
 		}
 	}
 	if meta["gasPrice"] != nil {
 		if _, err := cast.ToUint64E(meta["gasPrice"]); err != nil {
-			terr.Message += "invalid gas price"
-			return terr
+			return newError(ErrConstructionCheck, "invalid gas price")
 		}
 	}
 	if meta["nonce"] != nil {
 		if _, err := cast.ToUint64E(meta["nonce"]); err != nil {
-			terr.Message += "invalid nonce"
-			return terr
+			return newError(ErrConstructionCheck, "invalid nonce")
 		}
 	}
 	return nil
 }
 
 func (s *constructionAPIService) checkIoAction(act *iotextypes.Action, signed bool) (sender string, terr *types.Error) {
-	terr = ErrConstructionCheck
 	if _, ok := new(big.Int).SetString(act.GetCore().GetGasPrice(), 10); !ok {
-		terr.Message += "invalid gas price"
-		return "", terr
+		return "", newError(ErrConstructionCheck, "invalid gas price")
 	}
 
 	if !signed {
@@ -640,32 +682,26 @@ func (s *constructionAPIService) checkIoAction(act *iotextypes.Action, signed bo
 
 	// check pubkey and address
 	if len(act.GetSenderPubKey()) == 0 {
-		terr.Message += "invalid pub key"
-		return "", terr
+		return "", newError(ErrConstructionCheck, "invalid pub key")
 	}
 
 	pub, err := crypto.BytesToPublicKey(act.GetSenderPubKey())
 	if err != nil {
-		terr.Message += "invalid pub key"
-		return "", terr
+		return "", newError(ErrConstructionCheck, "invalid pub key")
 	}
 	senderAddr, err := address.FromBytes(pub.Hash())
 	if err != nil {
-		terr.Message += "invalid io address"
-		return "", terr
+		return "", newError(ErrConstructionCheck, "invalid io address")
 	}
 	sender = senderAddr.String()
 
 	core, err := proto.Marshal(act.GetCore())
 	if err != nil {
-		terr = ErrServiceInternal
-		terr.Message += err.Error()
-		return "", terr
+		return "", wrapError(ErrServiceInternal, err)
 	}
 	h := hash.Hash256b(core)
 	if !pub.Verify(h[:], act.GetSignature()) {
-		terr.Message += "invalid signature"
-		return "", terr
+		return "", newError(ErrConstructionCheck, "invalid signature")
 	}
 	return sender, nil
 }
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"math/big"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+)
+
+var (
+	depositToRewardingFundType = iotextypes.TransactionLogType_DEPOSIT_TO_REWARDING_FUND.String()
+	claimFromRewardingFundType = iotextypes.TransactionLogType_CLAIM_FROM_REWARDING_FUND.String()
+)
+
+// checkRewardingOps checks the operations of a deposit or a claim, the first
+// operation is always the signer's and the second one the rewarding pool's.
+// A deposit moves funds from the signer to the pool and a claim the other way.
+func checkRewardingOps(ops []*types.Operation, currency *types.Currency) *types.Error {
+	if len(ops) != 2 {
+		return newError(ErrConstructionCheck, "operation numbers are no expected")
+	}
+	if ops[0].Amount == nil || ops[1].Amount == nil {
+		return newError(ErrConstructionCheck, "amount value don't match")
+	}
+
+	// check amount
+	amount := ops[1].Amount.Value
+	if ops[0].Type == claimFromRewardingFundType {
+		amount = ops[0].Amount.Value
+	}
+	senderAmount, poolAmount := rewardingAmounts(ops[0].Type, amount)
+	if ops[0].Amount.Value != senderAmount || ops[1].Amount.Value != poolAmount {
+		return newError(ErrConstructionCheck, "amount value don't match")
+	}
+	_, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		return newError(ErrConstructionCheck, "amount value is invalid")
+	}
+
+	// check currency
+	for _, op := range ops {
+		if op.Amount.Currency.Symbol != currency.Symbol || op.Amount.Currency.Decimals != currency.Decimals {
+			return newError(ErrConstructionCheck, "invalid currency")
+		}
+	}
+
+	// check address
+	_, err := address.FromString(ops[0].Account.Address)
+	if err != nil {
+		return newError(ErrConstructionCheck, "invalid sender address")
+	}
+	if ops[1].Account.Address != address.RewardingPoolAddr {
+		return newError(ErrConstructionCheck, "recipient must be the rewarding pool")
+	}
+	return nil
+}
+
+// rewardingAmounts returns the signed amounts of the signer and the pool.
+func rewardingAmounts(typ, amount string) (senderAmount, poolAmount string) {
+	if typ == claimFromRewardingFundType {
+		return amount, "-" + amount
+	}
+	return "-" + amount, amount
+}
+
+func ioRewardingToOps(sender, typ, amount string, currency *types.Currency) []*types.Operation {
+	senderAmount, poolAmount := rewardingAmounts(typ, amount)
+	return []*types.Operation{
+		&types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 0,
+			},
+			Type: typ,
+			Account: &types.AccountIdentifier{
+				Address: sender,
+			},
+			Amount: &types.Amount{
+				Value:    senderAmount,
+				Currency: currency,
+			},
+		},
+		&types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 1,
+			},
+			RelatedOperations: []*types.OperationIdentifier{&types.OperationIdentifier{
+				Index: 0,
+			}},
+			Type: typ,
+			Account: &types.AccountIdentifier{
+				Address: address.RewardingPoolAddr,
+			},
+			Amount: &types.Amount{
+				Value:    poolAmount,
+				Currency: currency,
+			},
+		},
+	}
+}
+
+func opsToIoDepositToRewardingFund(ops []*types.Operation) *iotextypes.DepositToRewardingFund {
+	return &iotextypes.DepositToRewardingFund{
+		Amount: ops[1].Amount.Value,
+	}
+}
+
+func opsToIoClaimFromRewardingFund(ops []*types.Operation) *iotextypes.ClaimFromRewardingFund {
+	return &iotextypes.ClaimFromRewardingFund{
+		Amount: ops[0].Amount.Value,
+	}
+}
@@ -0,0 +1,315 @@
+package services
+
+import (
+	"math/big"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/spf13/cast"
+)
+
+const (
+	// staking operation types which have no matching transaction log type
+	StakeUnstakeType           = "STAKE_UNSTAKE"
+	StakeRestakeType           = "STAKE_RESTAKE"
+	StakeChangeCandidateType   = "STAKE_CHANGE_CANDIDATE"
+	StakeTransferOwnershipType = "STAKE_TRANSFER_OWNERSHIP"
+
+	// keys of the staking parameters in the first operation's metadata
+	candidateKey   = "candidate"
+	durationKey    = "duration"
+	autoStakeKey   = "autoStake"
+	bucketIndexKey = "bucketIndex"
+)
+
+var (
+	stakeCreateType     = iotextypes.TransactionLogType_CREATE_BUCKET.String()
+	stakeAddDepositType = iotextypes.TransactionLogType_DEPOSIT_TO_BUCKET.String()
+	stakeWithdrawType   = iotextypes.TransactionLogType_WITHDRAW_BUCKET.String()
+)
+
+func checkStakeValueOps(ops []*types.Operation, currency *types.Currency) *types.Error {
+	if len(ops) != 2 {
+		return newError(ErrConstructionCheck, "operation numbers are no expected")
+	}
+	if ops[0].Type != ops[1].Type {
+		return newError(ErrConstructionCheck, "operation types don't match")
+	}
+
+	// check amount
+	if ops[0].Amount == nil || ops[1].Amount == nil || ops[0].Amount.Value != "-"+ops[1].Amount.Value {
+		return newError(ErrConstructionCheck, "amount value don't match")
+	}
+	amount, ok := new(big.Int).SetString(ops[1].Amount.Value, 10)
+	if !ok || amount.Sign() <= 0 {
+		return newError(ErrConstructionCheck, "amount value is invalid")
+	}
+
+	// check currency
+	if ops[1].Amount.Currency == nil {
+		return newError(ErrConstructionCheck, "invalid currency")
+	}
+	symbol := ops[1].Amount.Currency.Symbol
+	decimals := ops[1].Amount.Currency.Decimals
+	if symbol != currency.Symbol || decimals != currency.Decimals {
+		return newError(ErrConstructionCheck, "invalid currency")
+	}
+
+	// check address
+	if ops[0].Account == nil || ops[1].Account == nil {
+		return newError(ErrConstructionCheck, "account is missing")
+	}
+	if _, err := address.FromString(ops[0].Account.Address); err != nil {
+		return newError(ErrConstructionCheck, "invalid sender address")
+	}
+	if ops[1].Account.Address != address.StakingBucketPoolAddr {
+		return newError(ErrConstructionCheck, "recipient must be the staking bucket pool")
+	}
+	return nil
+}
+
+func checkStakeCreateOps(ops []*types.Operation, currency *types.Currency) *types.Error {
+	if terr := checkStakeValueOps(ops, currency); terr != nil {
+		return terr
+	}
+	meta := ops[0].Metadata
+	if candidate, err := cast.ToStringE(meta[candidateKey]); err != nil || candidate == "" {
+		return newError(ErrConstructionCheck, "invalid candidate name")
+	}
+	if _, err := cast.ToUint32E(meta[durationKey]); err != nil {
+		return newError(ErrConstructionCheck, "invalid staked duration")
+	}
+	if _, err := cast.ToBoolE(meta[autoStakeKey]); err != nil {
+		return newError(ErrConstructionCheck, "invalid auto stake")
+	}
+	return nil
+}
+
+func checkStakeAddDepositOps(ops []*types.Operation, currency *types.Currency) *types.Error {
+	if terr := checkStakeValueOps(ops, currency); terr != nil {
+		return terr
+	}
+	return checkBucketIndex(ops[0])
+}
+
+func checkStakeBucketOps(ops []*types.Operation) *types.Error {
+	if len(ops) != 1 {
+		return newError(ErrConstructionCheck, "operation numbers are no expected")
+	}
+	if ops[0].Amount != nil {
+		return newError(ErrConstructionCheck, "amount is not expected")
+	}
+	if ops[0].Account == nil {
+		return newError(ErrConstructionCheck, "account is missing")
+	}
+	if _, err := address.FromString(ops[0].Account.Address); err != nil {
+		return newError(ErrConstructionCheck, "invalid sender address")
+	}
+	return checkBucketIndex(ops[0])
+}
+
+func checkStakeRestakeOps(ops []*types.Operation) *types.Error {
+	if terr := checkStakeBucketOps(ops); terr != nil {
+		return terr
+	}
+	meta := ops[0].Metadata
+	if _, err := cast.ToUint32E(meta[durationKey]); err != nil {
+		return newError(ErrConstructionCheck, "invalid staked duration")
+	}
+	if _, err := cast.ToBoolE(meta[autoStakeKey]); err != nil {
+		return newError(ErrConstructionCheck, "invalid auto stake")
+	}
+	return nil
+}
+
+func checkStakeChangeCandidateOps(ops []*types.Operation) *types.Error {
+	if terr := checkStakeBucketOps(ops); terr != nil {
+		return terr
+	}
+	if candidate, err := cast.ToStringE(ops[0].Metadata[candidateKey]); err != nil || candidate == "" {
+		return newError(ErrConstructionCheck, "invalid candidate name")
+	}
+	return nil
+}
+
+func checkStakeTransferOwnershipOps(ops []*types.Operation) *types.Error {
+	if len(ops) != 2 {
+		return newError(ErrConstructionCheck, "operation numbers are no expected")
+	}
+	if ops[0].Type != ops[1].Type {
+		return newError(ErrConstructionCheck, "operation types don't match")
+	}
+	if ops[0].Amount != nil || ops[1].Amount != nil {
+		return newError(ErrConstructionCheck, "amount is not expected")
+	}
+	if ops[0].Account == nil || ops[1].Account == nil {
+		return newError(ErrConstructionCheck, "account is missing")
+	}
+	if _, err := address.FromString(ops[0].Account.Address); err != nil {
+		return newError(ErrConstructionCheck, "invalid sender address")
+	}
+	if _, err := address.FromString(ops[1].Account.Address); err != nil {
+		return newError(ErrConstructionCheck, "invalid new owner address")
+	}
+	return checkBucketIndex(ops[0])
+}
+
+func checkBucketIndex(op *types.Operation) *types.Error {
+	if _, ok := op.Metadata[bucketIndexKey]; !ok {
+		return newError(ErrConstructionCheck, "empty bucket index")
+	}
+	if _, err := cast.ToUint64E(op.Metadata[bucketIndexKey]); err != nil {
+		return newError(ErrConstructionCheck, "invalid bucket index")
+	}
+	return nil
+}
+
+func opsToIoStakeAction(core *iotextypes.ActionCore, ops []*types.Operation) {
+	meta := ops[0].Metadata
+	bucketIndex := cast.ToUint64(meta[bucketIndexKey])
+	switch ops[0].Type {
+	case stakeCreateType:
+		core.Action = &iotextypes.ActionCore_StakeCreate{StakeCreate: &iotextypes.StakeCreate{
+			CandidateName:  cast.ToString(meta[candidateKey]),
+			StakedAmount:   ops[1].Amount.Value,
+			StakedDuration: cast.ToUint32(meta[durationKey]),
+			AutoStake:      cast.ToBool(meta[autoStakeKey]),
+		}}
+	case stakeAddDepositType:
+		core.Action = &iotextypes.ActionCore_StakeAddDeposit{StakeAddDeposit: &iotextypes.StakeAddDeposit{
+			BucketIndex: bucketIndex,
+			Amount:      ops[1].Amount.Value,
+		}}
+	case StakeUnstakeType:
+		core.Action = &iotextypes.ActionCore_StakeUnstake{StakeUnstake: &iotextypes.StakeReclaim{
+			BucketIndex: bucketIndex,
+		}}
+	case stakeWithdrawType:
+		core.Action = &iotextypes.ActionCore_StakeWithdraw{StakeWithdraw: &iotextypes.StakeReclaim{
+			BucketIndex: bucketIndex,
+		}}
+	case StakeRestakeType:
+		core.Action = &iotextypes.ActionCore_StakeRestake{StakeRestake: &iotextypes.StakeRestake{
+			BucketIndex:    bucketIndex,
+			StakedDuration: cast.ToUint32(meta[durationKey]),
+			AutoStake:      cast.ToBool(meta[autoStakeKey]),
+		}}
+	case StakeChangeCandidateType:
+		core.Action = &iotextypes.ActionCore_StakeChangeCandidate{StakeChangeCandidate: &iotextypes.StakeChangeCandidate{
+			BucketIndex:   bucketIndex,
+			CandidateName: cast.ToString(meta[candidateKey]),
+		}}
+	case StakeTransferOwnershipType:
+		core.Action = &iotextypes.ActionCore_StakeTransferOwnership{StakeTransferOwnership: &iotextypes.StakeTransferOwnership{
+			BucketIndex:  bucketIndex,
+			VoterAddress: ops[1].Account.Address,
+		}}
+	}
+}
+
+func ioStakeActionToOps(sender string, core *iotextypes.ActionCore, currency *types.Currency) []*types.Operation {
+	switch {
+	case core.GetStakeCreate() != nil:
+		act := core.GetStakeCreate()
+		return stakeValueOps(sender, stakeCreateType, act.GetStakedAmount(), currency, map[string]interface{}{
+			candidateKey: act.GetCandidateName(),
+			durationKey:  act.GetStakedDuration(),
+			autoStakeKey: act.GetAutoStake(),
+		})
+	case core.GetStakeAddDeposit() != nil:
+		act := core.GetStakeAddDeposit()
+		return stakeValueOps(sender, stakeAddDepositType, act.GetAmount(), currency, map[string]interface{}{
+			bucketIndexKey: act.GetBucketIndex(),
+		})
+	case core.GetStakeUnstake() != nil:
+		return stakeBucketOps(sender, StakeUnstakeType, map[string]interface{}{
+			bucketIndexKey: core.GetStakeUnstake().GetBucketIndex(),
+		})
+	case core.GetStakeWithdraw() != nil:
+		return stakeBucketOps(sender, stakeWithdrawType, map[string]interface{}{
+			bucketIndexKey: core.GetStakeWithdraw().GetBucketIndex(),
+		})
+	case core.GetStakeRestake() != nil:
+		act := core.GetStakeRestake()
+		return stakeBucketOps(sender, StakeRestakeType, map[string]interface{}{
+			bucketIndexKey: act.GetBucketIndex(),
+			durationKey:    act.GetStakedDuration(),
+			autoStakeKey:   act.GetAutoStake(),
+		})
+	case core.GetStakeChangeCandidate() != nil:
+		act := core.GetStakeChangeCandidate()
+		return stakeBucketOps(sender, StakeChangeCandidateType, map[string]interface{}{
+			bucketIndexKey: act.GetBucketIndex(),
+			candidateKey:   act.GetCandidateName(),
+		})
+	case core.GetStakeTransferOwnership() != nil:
+		act := core.GetStakeTransferOwnership()
+		ops := stakeBucketOps(sender, StakeTransferOwnershipType, map[string]interface{}{
+			bucketIndexKey: act.GetBucketIndex(),
+		})
+		return append(ops, &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 1,
+			},
+			RelatedOperations: []*types.OperationIdentifier{&types.OperationIdentifier{
+				Index: 0,
+			}},
+			Type: StakeTransferOwnershipType,
+			Account: &types.AccountIdentifier{
+				Address: act.GetVoterAddress(),
+			},
+		})
+	}
+	return nil
+}
+
+func stakeValueOps(sender, typ, amount string, currency *types.Currency, meta map[string]interface{}) []*types.Operation {
+	return []*types.Operation{
+		&types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 0,
+			},
+			Type: typ,
+			Account: &types.AccountIdentifier{
+				Address: sender,
+			},
+			Amount: &types.Amount{
+				Value:    "-" + amount,
+				Currency: currency,
+			},
+			Metadata: meta,
+		},
+		&types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 1,
+			},
+			RelatedOperations: []*types.OperationIdentifier{&types.OperationIdentifier{
+				Index: 0,
+			}},
+			Type: typ,
+			Account: &types.AccountIdentifier{
+				Address: address.StakingBucketPoolAddr,
+			},
+			Amount: &types.Amount{
+				Value:    amount,
+				Currency: currency,
+			},
+		},
+	}
+}
+
+func stakeBucketOps(sender, typ string, meta map[string]interface{}) []*types.Operation {
+	return []*types.Operation{
+		&types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 0,
+			},
+			Type: typ,
+			Account: &types.AccountIdentifier{
+				Address: sender,
+			},
+			Metadata: meta,
+		},
+	}
+}
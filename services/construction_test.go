@@ -7,9 +7,11 @@ import (
 
 	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/golang/mock/gomock"
+	"github.com/iotexproject/iotex-address/address"
 	"github.com/iotexproject/iotex-proto/golang/iotextypes"
 	"github.com/stretchr/testify/require"
 
+	"github.com/iotexproject/iotex-core-rosetta-gateway/config"
 	ic "github.com/iotexproject/iotex-core-rosetta-gateway/iotex-client"
 	"github.com/iotexproject/iotex-core-rosetta-gateway/iotex-client/mock_client"
 )
@@ -162,7 +164,7 @@ func TestConstructionAPIService_ConstructionMetadata(t *testing.T) {
 		}
 	)
 	cli.EXPECT().GetConfig().Return(cfg).AnyTimes()
-	cli.EXPECT().GetAccount(gomock.Any(), gomock.Eq(int64(0)), gomock.AssignableToTypeOf("")).
+	cli.EXPECT().GetAccount(gomock.Any(), gomock.Eq(int64(0)), gomock.AssignableToTypeOf(""), gomock.Nil()).
 		Return(accountBalanceResp, nil).AnyTimes()
 	cli.EXPECT().EstimateGasForAction(gomock.Any(), gomock.AssignableToTypeOf(&iotextypes.Action{})).
 		Return(gasLimit, nil).AnyTimes()
@@ -178,6 +180,33 @@ func TestConstructionAPIService_ConstructionMetadata(t *testing.T) {
 	require.Equal(ret, resp)
 }
 
+func TestParseMetadataInputOptions(t *testing.T) {
+	var (
+		require = require.New(t)
+		sender  = "io13rjq2c07mqhe8sdd7nf9a4vcmnyk9mn72hu94e"
+		typ     = iotextypes.TransactionLogType_NATIVE_TRANSFER.String()
+		tests   = []struct {
+			options map[string]interface{}
+			field   string
+		}{
+			{map[string]interface{}{"type": typ}, "sender"},
+			{map[string]interface{}{"sender": []int{1}, "type": typ}, "sender"},
+			{map[string]interface{}{"sender": sender}, "type"},
+			{map[string]interface{}{"sender": sender, "type": "UNKNOWN"}, "type"},
+			{map[string]interface{}{"sender": sender, "type": typ, "gasLimit": "x"}, "gasLimit"},
+			{map[string]interface{}{"sender": sender, "type": typ, "gasPrice": "x"}, "gasPrice"},
+			{map[string]interface{}{"sender": sender, "type": typ, "feeMultiplier": "x"}, "feeMultiplier"},
+			{map[string]interface{}{"sender": sender, "type": typ, "maxFee": "x"}, "maxFee"},
+		}
+	)
+	for i, test := range tests {
+		_, typErr := parseMetadataInputOptions(test.options)
+		require.NotNil(typErr, "index: %d", i)
+		require.Equal(ErrInvalidInputParam.Code, typErr.Code, "index: %d", i)
+		require.Equal(test.field, typErr.Details[fieldKey], "index: %d", i)
+	}
+}
+
 func TestConstructionAPIService_ConstructionParse(t *testing.T) {
 	var (
 		cfg               = testConfig()
@@ -472,3 +501,525 @@ func TestConstructionAPIService_ConstructionSubmit(t *testing.T) {
 	require.Nil(typErr)
 	require.Equal(ret, resp)
 }
+
+func TestConstructionAPIService_StakingRoundTrip(t *testing.T) {
+	var (
+		cfg               = testConfig()
+		networkIdentifier = &types.NetworkIdentifier{
+			Blockchain: "IoTeX",
+			Network:    "testnet",
+		}
+		currency = &types.Currency{
+			Symbol:   "IOTX",
+			Decimals: 18,
+		}
+		sender   = &types.AccountIdentifier{Address: "io1mflp9m6hcgm2qcghchsdqj3z3eccrnekx9p0ms"}
+		newOwner = &types.AccountIdentifier{Address: "io1jh0ekmccywfkmj7e8qsuzsupnlk3w5337hjjg2"}
+		pool     = &types.AccountIdentifier{Address: address.StakingBucketPoolAddr}
+		meta     = map[string]interface{}{
+			"gasLimit": uint64(10000),
+			"gasPrice": uint64(1000000000000),
+			"nonce":    uint64(3),
+		}
+
+		require = require.New(t)
+		ctrl    = gomock.NewController(t)
+		cli     = mock_client.NewMockIoTexClient(ctrl)
+		clt     = NewConstructionAPIService(cli)
+		tests   = [][]*types.Operation{
+			{
+				{
+					OperationIdentifier: &types.OperationIdentifier{Index: 0},
+					Type:                iotextypes.TransactionLogType_CREATE_BUCKET.String(),
+					Account:             sender,
+					Amount:              &types.Amount{Value: "-100000000000000000000", Currency: currency},
+					Metadata: map[string]interface{}{
+						"candidate": "robotbp00000",
+						"duration":  uint32(91),
+						"autoStake": true,
+					},
+				}, {
+					OperationIdentifier: &types.OperationIdentifier{Index: 1},
+					RelatedOperations:   []*types.OperationIdentifier{{Index: 0}},
+					Type:                iotextypes.TransactionLogType_CREATE_BUCKET.String(),
+					Account:             pool,
+					Amount:              &types.Amount{Value: "100000000000000000000", Currency: currency},
+				},
+			},
+			{
+				{
+					OperationIdentifier: &types.OperationIdentifier{Index: 0},
+					Type:                iotextypes.TransactionLogType_DEPOSIT_TO_BUCKET.String(),
+					Account:             sender,
+					Amount:              &types.Amount{Value: "-5", Currency: currency},
+					Metadata:            map[string]interface{}{"bucketIndex": uint64(7)},
+				}, {
+					OperationIdentifier: &types.OperationIdentifier{Index: 1},
+					RelatedOperations:   []*types.OperationIdentifier{{Index: 0}},
+					Type:                iotextypes.TransactionLogType_DEPOSIT_TO_BUCKET.String(),
+					Account:             pool,
+					Amount:              &types.Amount{Value: "5", Currency: currency},
+				},
+			},
+			{
+				{
+					OperationIdentifier: &types.OperationIdentifier{Index: 0},
+					Type:                StakeUnstakeType,
+					Account:             sender,
+					Metadata:            map[string]interface{}{"bucketIndex": uint64(7)},
+				},
+			},
+			{
+				{
+					OperationIdentifier: &types.OperationIdentifier{Index: 0},
+					Type:                iotextypes.TransactionLogType_WITHDRAW_BUCKET.String(),
+					Account:             sender,
+					Metadata:            map[string]interface{}{"bucketIndex": uint64(7)},
+				},
+			},
+			{
+				{
+					OperationIdentifier: &types.OperationIdentifier{Index: 0},
+					Type:                StakeRestakeType,
+					Account:             sender,
+					Metadata: map[string]interface{}{
+						"bucketIndex": uint64(7),
+						"duration":    uint32(14),
+						"autoStake":   false,
+					},
+				},
+			},
+			{
+				{
+					OperationIdentifier: &types.OperationIdentifier{Index: 0},
+					Type:                StakeChangeCandidateType,
+					Account:             sender,
+					Metadata: map[string]interface{}{
+						"bucketIndex": uint64(7),
+						"candidate":   "iotexlab",
+					},
+				},
+			},
+			{
+				{
+					OperationIdentifier: &types.OperationIdentifier{Index: 0},
+					Type:                StakeTransferOwnershipType,
+					Account:             sender,
+					Metadata:            map[string]interface{}{"bucketIndex": uint64(7)},
+				}, {
+					OperationIdentifier: &types.OperationIdentifier{Index: 1},
+					RelatedOperations:   []*types.OperationIdentifier{{Index: 0}},
+					Type:                StakeTransferOwnershipType,
+					Account:             newOwner,
+				},
+			},
+		}
+	)
+	cli.EXPECT().GetConfig().Return(cfg).AnyTimes()
+	for i, ops := range tests {
+		_, typErr := clt.ConstructionPreprocess(context.Background(), &types.ConstructionPreprocessRequest{
+			NetworkIdentifier: networkIdentifier,
+			Operations:        ops,
+		})
+		require.Nil(typErr, "index: %d", i)
+
+		payloads, typErr := clt.ConstructionPayloads(context.Background(), &types.ConstructionPayloadsRequest{
+			NetworkIdentifier: networkIdentifier,
+			Operations:        ops,
+			Metadata:          meta,
+		})
+		require.Nil(typErr, "index: %d", i)
+
+		parsed, typErr := clt.ConstructionParse(context.Background(), &types.ConstructionParseRequest{
+			NetworkIdentifier: networkIdentifier,
+			Signed:            false,
+			Transaction:       payloads.UnsignedTransaction,
+		})
+		require.Nil(typErr, "index: %d", i)
+		require.Equal(ops, parsed.Operations, "index: %d", i)
+		require.Equal(meta, parsed.Metadata, "index: %d", i)
+	}
+}
+
+func TestConstructionAPIService_StakingCheck(t *testing.T) {
+	var (
+		cfg               = testConfig()
+		networkIdentifier = &types.NetworkIdentifier{
+			Blockchain: "IoTeX",
+			Network:    "testnet",
+		}
+		currency = &types.Currency{
+			Symbol:   "IOTX",
+			Decimals: 18,
+		}
+		sender = &types.AccountIdentifier{Address: "io1mflp9m6hcgm2qcghchsdqj3z3eccrnekx9p0ms"}
+
+		require = require.New(t)
+		ctrl    = gomock.NewController(t)
+		cli     = mock_client.NewMockIoTexClient(ctrl)
+		clt     = NewConstructionAPIService(cli)
+		tests   = [][]*types.Operation{
+			// recipient is not the bucket pool
+			{
+				{
+					OperationIdentifier: &types.OperationIdentifier{Index: 0},
+					Type:                iotextypes.TransactionLogType_CREATE_BUCKET.String(),
+					Account:             sender,
+					Amount:              &types.Amount{Value: "-1", Currency: currency},
+					Metadata:            map[string]interface{}{"candidate": "iotexlab", "duration": 1, "autoStake": true},
+				}, {
+					OperationIdentifier: &types.OperationIdentifier{Index: 1},
+					Type:                iotextypes.TransactionLogType_CREATE_BUCKET.String(),
+					Account:             sender,
+					Amount:              &types.Amount{Value: "1", Currency: currency},
+				},
+			},
+			// zero amount
+			{
+				{
+					OperationIdentifier: &types.OperationIdentifier{Index: 0},
+					Type:                iotextypes.TransactionLogType_CREATE_BUCKET.String(),
+					Account:             sender,
+					Amount:              &types.Amount{Value: "-0", Currency: currency},
+					Metadata:            map[string]interface{}{"candidate": "iotexlab", "duration": 1, "autoStake": true},
+				}, {
+					OperationIdentifier: &types.OperationIdentifier{Index: 1},
+					Type:                iotextypes.TransactionLogType_CREATE_BUCKET.String(),
+					Account:             &types.AccountIdentifier{Address: address.StakingBucketPoolAddr},
+					Amount:              &types.Amount{Value: "0", Currency: currency},
+				},
+			},
+			// negative amount
+			{
+				{
+					OperationIdentifier: &types.OperationIdentifier{Index: 0},
+					Type:                iotextypes.TransactionLogType_CREATE_BUCKET.String(),
+					Account:             sender,
+					Amount:              &types.Amount{Value: "--1", Currency: currency},
+					Metadata:            map[string]interface{}{"candidate": "iotexlab", "duration": 1, "autoStake": true},
+				}, {
+					OperationIdentifier: &types.OperationIdentifier{Index: 1},
+					Type:                iotextypes.TransactionLogType_CREATE_BUCKET.String(),
+					Account:             &types.AccountIdentifier{Address: address.StakingBucketPoolAddr},
+					Amount:              &types.Amount{Value: "-1", Currency: currency},
+				},
+			},
+			// operation types don't match
+			{
+				{
+					OperationIdentifier: &types.OperationIdentifier{Index: 0},
+					Type:                iotextypes.TransactionLogType_CREATE_BUCKET.String(),
+					Account:             sender,
+					Amount:              &types.Amount{Value: "-1", Currency: currency},
+					Metadata:            map[string]interface{}{"candidate": "iotexlab", "duration": 1, "autoStake": true},
+				}, {
+					OperationIdentifier: &types.OperationIdentifier{Index: 1},
+					Type:                iotextypes.TransactionLogType_NATIVE_TRANSFER.String(),
+					Account:             &types.AccountIdentifier{Address: address.StakingBucketPoolAddr},
+					Amount:              &types.Amount{Value: "1", Currency: currency},
+				},
+			},
+			// missing currency
+			{
+				{
+					OperationIdentifier: &types.OperationIdentifier{Index: 0},
+					Type:                iotextypes.TransactionLogType_CREATE_BUCKET.String(),
+					Account:             sender,
+					Amount:              &types.Amount{Value: "-1"},
+					Metadata:            map[string]interface{}{"candidate": "iotexlab", "duration": 1, "autoStake": true},
+				}, {
+					OperationIdentifier: &types.OperationIdentifier{Index: 1},
+					Type:                iotextypes.TransactionLogType_CREATE_BUCKET.String(),
+					Account:             &types.AccountIdentifier{Address: address.StakingBucketPoolAddr},
+					Amount:              &types.Amount{Value: "1"},
+				},
+			},
+			// missing account
+			{
+				{
+					OperationIdentifier: &types.OperationIdentifier{Index: 0},
+					Type:                iotextypes.TransactionLogType_CREATE_BUCKET.String(),
+					Amount:              &types.Amount{Value: "-1", Currency: currency},
+					Metadata:            map[string]interface{}{"candidate": "iotexlab", "duration": 1, "autoStake": true},
+				}, {
+					OperationIdentifier: &types.OperationIdentifier{Index: 1},
+					Type:                iotextypes.TransactionLogType_CREATE_BUCKET.String(),
+					Account:             &types.AccountIdentifier{Address: address.StakingBucketPoolAddr},
+					Amount:              &types.Amount{Value: "1", Currency: currency},
+				},
+			},
+			// missing bucket index
+			{
+				{
+					OperationIdentifier: &types.OperationIdentifier{Index: 0},
+					Type:                StakeUnstakeType,
+					Account:             sender,
+				},
+			},
+			// missing candidate
+			{
+				{
+					OperationIdentifier: &types.OperationIdentifier{Index: 0},
+					Type:                StakeChangeCandidateType,
+					Account:             sender,
+					Metadata:            map[string]interface{}{"bucketIndex": 1},
+				},
+			},
+			// missing new owner
+			{
+				{
+					OperationIdentifier: &types.OperationIdentifier{Index: 0},
+					Type:                StakeTransferOwnershipType,
+					Account:             sender,
+					Metadata:            map[string]interface{}{"bucketIndex": 1},
+				},
+			},
+		}
+	)
+	cli.EXPECT().GetConfig().Return(cfg).AnyTimes()
+	for i, ops := range tests {
+		_, typErr := clt.ConstructionPreprocess(context.Background(), &types.ConstructionPreprocessRequest{
+			NetworkIdentifier: networkIdentifier,
+			Operations:        ops,
+		})
+		require.NotNil(typErr, "index: %d", i)
+		require.Equal(ErrConstructionCheck.Code, typErr.Code, "index: %d", i)
+	}
+}
+
+func TestConstructionAPIService_RewardingRoundTrip(t *testing.T) {
+	var (
+		cfg               = testConfig()
+		networkIdentifier = &types.NetworkIdentifier{
+			Blockchain: "IoTeX",
+			Network:    "testnet",
+		}
+		currency = &types.Currency{
+			Symbol:   "IOTX",
+			Decimals: 18,
+		}
+		sender = &types.AccountIdentifier{Address: "io1mflp9m6hcgm2qcghchsdqj3z3eccrnekx9p0ms"}
+		pool   = &types.AccountIdentifier{Address: address.RewardingPoolAddr}
+		meta   = map[string]interface{}{
+			"gasLimit": uint64(10000),
+			"gasPrice": uint64(1000000000000),
+			"nonce":    uint64(3),
+		}
+
+		require = require.New(t)
+		ctrl    = gomock.NewController(t)
+		cli     = mock_client.NewMockIoTexClient(ctrl)
+		clt     = NewConstructionAPIService(cli)
+		tests   = []struct {
+			typ          string
+			senderAmount string
+			poolAmount   string
+			err          bool
+		}{
+			{iotextypes.TransactionLogType_DEPOSIT_TO_REWARDING_FUND.String(), "-100", "100", false},
+			{iotextypes.TransactionLogType_CLAIM_FROM_REWARDING_FUND.String(), "100", "-100", false},
+			{iotextypes.TransactionLogType_DEPOSIT_TO_REWARDING_FUND.String(), "100", "-100", true},
+			{iotextypes.TransactionLogType_CLAIM_FROM_REWARDING_FUND.String(), "-100", "100", true},
+		}
+	)
+	cli.EXPECT().GetConfig().Return(cfg).AnyTimes()
+	for i, test := range tests {
+		ops := []*types.Operation{
+			{
+				OperationIdentifier: &types.OperationIdentifier{Index: 0},
+				Type:                test.typ,
+				Account:             sender,
+				Amount:              &types.Amount{Value: test.senderAmount, Currency: currency},
+			}, {
+				OperationIdentifier: &types.OperationIdentifier{Index: 1},
+				RelatedOperations:   []*types.OperationIdentifier{{Index: 0}},
+				Type:                test.typ,
+				Account:             pool,
+				Amount:              &types.Amount{Value: test.poolAmount, Currency: currency},
+			},
+		}
+		payloads, typErr := clt.ConstructionPayloads(context.Background(), &types.ConstructionPayloadsRequest{
+			NetworkIdentifier: networkIdentifier,
+			Operations:        ops,
+			Metadata:          meta,
+		})
+		if test.err {
+			require.NotNil(typErr, "index: %d", i)
+			continue
+		}
+		require.Nil(typErr, "index: %d", i)
+
+		parsed, typErr := clt.ConstructionParse(context.Background(), &types.ConstructionParseRequest{
+			NetworkIdentifier: networkIdentifier,
+			Signed:            false,
+			Transaction:       payloads.UnsignedTransaction,
+		})
+		require.Nil(typErr, "index: %d", i)
+		require.Equal(ops, parsed.Operations, "index: %d", i)
+	}
+}
+
+func TestConstructionAPIService_RewardingMetadata(t *testing.T) {
+	var (
+		cfg               = testConfig()
+		networkIdentifier = &types.NetworkIdentifier{
+			Blockchain: "IoTeX",
+			Network:    "testnet",
+		}
+		gasLimit = uint64(10000)
+
+		require = require.New(t)
+		ctrl    = gomock.NewController(t)
+		cli     = mock_client.NewMockIoTexClient(ctrl)
+		clt     = NewConstructionAPIService(cli)
+	)
+	cli.EXPECT().GetConfig().Return(cfg).AnyTimes()
+	cli.EXPECT().GetAccount(gomock.Any(), gomock.Eq(int64(0)), gomock.AssignableToTypeOf(""), gomock.Nil()).
+		Return(&types.AccountBalanceResponse{Metadata: map[string]interface{}{ic.NonceKey: uint64(1)}}, nil).AnyTimes()
+	cli.EXPECT().SuggestGasPrice(gomock.Any()).Return(uint64(1), nil).AnyTimes()
+	cli.EXPECT().EstimateGasForAction(gomock.Any(), gomock.AssignableToTypeOf(&iotextypes.Action{})).
+		DoAndReturn(func(_ context.Context, act *iotextypes.Action) (uint64, error) {
+			claim := act.GetCore().GetClaimFromRewardingFund()
+			require.NotNil(claim)
+			require.Equal("0", claim.GetAmount())
+			return gasLimit, nil
+		}).Times(1)
+	resp, typErr := clt.ConstructionMetadata(context.Background(), &types.ConstructionMetadataRequest{
+		NetworkIdentifier: networkIdentifier,
+		Options: map[string]interface{}{
+			"sender": "io13rjq2c07mqhe8sdd7nf9a4vcmnyk9mn72hu94e",
+			"type":   iotextypes.TransactionLogType_CLAIM_FROM_REWARDING_FUND.String(),
+		},
+	})
+	require.Nil(typErr)
+	require.Equal(gasLimit, resp.Metadata["gasLimit"])
+}
+
+func TestConstructionAPIService_Xrc20RoundTrip(t *testing.T) {
+	var (
+		cfg               = testConfig()
+		networkIdentifier = &types.NetworkIdentifier{
+			Blockchain: "IoTeX",
+			Network:    "testnet",
+		}
+		token     = config.Token{Contract: "io1hp6y4eqr90j7tmul4w2wa8pm7wx462hq0mg4tw", Symbol: "VITA", Decimals: 18}
+		currency  = ic.TokenCurrency(token)
+		sender    = &types.AccountIdentifier{Address: "io1mflp9m6hcgm2qcghchsdqj3z3eccrnekx9p0ms"}
+		recipient = &types.AccountIdentifier{Address: "io13rjq2c07mqhe8sdd7nf9a4vcmnyk9mn72hu94e"}
+		meta      = map[string]interface{}{
+			"gasLimit": uint64(10000),
+			"gasPrice": uint64(1000000000000),
+			"nonce":    uint64(3),
+		}
+
+		require = require.New(t)
+		ctrl    = gomock.NewController(t)
+		cli     = mock_client.NewMockIoTexClient(ctrl)
+		clt     = NewConstructionAPIService(cli)
+	)
+	cfg.Tokens = []config.Token{token}
+	cli.EXPECT().GetConfig().Return(cfg).AnyTimes()
+	ops := []*types.Operation{
+		{
+			OperationIdentifier: &types.OperationIdentifier{Index: 0},
+			Type:                ic.Xrc20TransferType,
+			Account:             sender,
+			Amount:              &types.Amount{Value: "-100", Currency: currency},
+		}, {
+			OperationIdentifier: &types.OperationIdentifier{Index: 1},
+			RelatedOperations:   []*types.OperationIdentifier{{Index: 0}},
+			Type:                ic.Xrc20TransferType,
+			Account:             recipient,
+			Amount:              &types.Amount{Value: "100", Currency: currency},
+		},
+	}
+
+	pre, typErr := clt.ConstructionPreprocess(context.Background(), &types.ConstructionPreprocessRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations:        ops,
+	})
+	require.Nil(typErr)
+	require.Equal(token.Contract, pre.Options["contract"])
+	require.Equal(recipient.Address, pre.Options["recipient"])
+
+	payloads, typErr := clt.ConstructionPayloads(context.Background(), &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations:        ops,
+		Metadata:          meta,
+	})
+	require.Nil(typErr)
+
+	parsed, typErr := clt.ConstructionParse(context.Background(), &types.ConstructionParseRequest{
+		NetworkIdentifier: networkIdentifier,
+		Signed:            false,
+		Transaction:       payloads.UnsignedTransaction,
+	})
+	require.Nil(typErr)
+	require.Equal(ops, parsed.Operations)
+
+	// unknown token contracts are rejected
+	cfg.Tokens = nil
+	_, typErr = clt.ConstructionPayloads(context.Background(), &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations:        ops,
+		Metadata:          meta,
+	})
+	require.NotNil(typErr)
+	require.Equal(ErrConstructionCheck.Code, typErr.Code)
+}
+
+func TestConstructionAPIService_Xrc20Metadata(t *testing.T) {
+	var (
+		cfg               = testConfig()
+		networkIdentifier = &types.NetworkIdentifier{
+			Blockchain: "IoTeX",
+			Network:    "testnet",
+		}
+		sender    = "io1mflp9m6hcgm2qcghchsdqj3z3eccrnekx9p0ms"
+		recipient = "io13rjq2c07mqhe8sdd7nf9a4vcmnyk9mn72hu94e"
+		contract  = "io1hp6y4eqr90j7tmul4w2wa8pm7wx462hq0mg4tw"
+		gasLimit  = uint64(36000)
+
+		require = require.New(t)
+		ctrl    = gomock.NewController(t)
+		cli     = mock_client.NewMockIoTexClient(ctrl)
+		clt     = NewConstructionAPIService(cli)
+	)
+	cli.EXPECT().GetConfig().Return(cfg).AnyTimes()
+	cli.EXPECT().GetAccount(gomock.Any(), gomock.Eq(int64(0)), gomock.Eq(sender), gomock.Nil()).
+		Return(&types.AccountBalanceResponse{Metadata: map[string]interface{}{ic.NonceKey: uint64(1)}}, nil).AnyTimes()
+	cli.EXPECT().SuggestGasPrice(gomock.Any()).Return(uint64(1), nil).AnyTimes()
+	cli.EXPECT().EstimateExecutionGas(gomock.Any(), gomock.Eq(sender), gomock.AssignableToTypeOf(&iotextypes.Execution{})).
+		DoAndReturn(func(_ context.Context, _ string, execution *iotextypes.Execution) (uint64, error) {
+			require.Equal(contract, execution.GetContract())
+			to, amount, ok := ic.ParseXrc20TransferData(execution.GetData())
+			require.True(ok)
+			require.Equal(recipient, to)
+			require.Equal("100", amount.String())
+			return gasLimit, nil
+		}).Times(1)
+	resp, typErr := clt.ConstructionMetadata(context.Background(), &types.ConstructionMetadataRequest{
+		NetworkIdentifier: networkIdentifier,
+		Options: map[string]interface{}{
+			"sender":    sender,
+			"recipient": recipient,
+			"amount":    "100",
+			"contract":  contract,
+			"type":      ic.Xrc20TransferType,
+		},
+	})
+	require.Nil(typErr)
+	require.Equal(gasLimit, resp.Metadata["gasLimit"])
+
+	// the contract is required to estimate the gas
+	_, typErr = clt.ConstructionMetadata(context.Background(), &types.ConstructionMetadataRequest{
+		NetworkIdentifier: networkIdentifier,
+		Options: map[string]interface{}{
+			"sender":    sender,
+			"recipient": recipient,
+			"amount":    "100",
+			"type":      ic.Xrc20TransferType,
+		},
+	})
+	require.NotNil(typErr)
+}
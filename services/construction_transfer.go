@@ -9,42 +9,35 @@ import (
 )
 
 func checkTransferOps(ops []*types.Operation, currency *types.Currency) *types.Error {
-	terr := ErrConstructionCheck
 	if len(ops) != 2 {
-		terr.Message += "operation numbers are no expected"
-		return terr
+		return newError(ErrConstructionCheck, "operation numbers are no expected")
 	}
 
 	// check amount
 	if ops[0].Amount.Value != "-"+ops[1].Amount.Value {
-		terr.Message += "amount value don't match"
-		return terr
+		return newError(ErrConstructionCheck, "amount value don't match")
 	}
 	amountStr := ops[1].Amount.Value
 	_, ok := new(big.Int).SetString(amountStr, 10)
 	if !ok {
-		terr.Message += "amount value is invalid"
-		return terr
+		return newError(ErrConstructionCheck, "amount value is invalid")
 	}
 
 	// check currency
 	symbol := ops[1].Amount.Currency.Symbol
 	decimals := ops[1].Amount.Currency.Decimals
 	if symbol != currency.Symbol || decimals != currency.Decimals {
-		terr.Message += "invalid currency"
-		return terr
+		return newError(ErrConstructionCheck, "invalid currency")
 	}
 
 	// check address
 	_, err := address.FromString(ops[0].Account.Address)
 	if err != nil {
-		terr.Message += "invalid sender address"
-		return terr
+		return newError(ErrConstructionCheck, "invalid sender address")
 	}
 	_, err = address.FromString(ops[1].Account.Address)
 	if err != nil {
-		terr.Message += "invalid recipient address"
-		return terr
+		return newError(ErrConstructionCheck, "invalid recipient address")
 	}
 	return nil
 }
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"math/big"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/iotexproject/iotex-address/address"
+	"github.com/iotexproject/iotex-proto/golang/iotextypes"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+
+	"github.com/iotexproject/iotex-core-rosetta-gateway/config"
+	ic "github.com/iotexproject/iotex-core-rosetta-gateway/iotex-client"
+)
+
+func checkXrc20TransferOps(ops []*types.Operation, cfg *config.Config) *types.Error {
+	if len(ops) != 2 {
+		return newError(ErrConstructionCheck, "operation numbers are no expected")
+	}
+
+	// check amount
+	if ops[0].Amount == nil || ops[1].Amount == nil || ops[0].Amount.Value != "-"+ops[1].Amount.Value {
+		return newError(ErrConstructionCheck, "amount value don't match")
+	}
+	amount, ok := new(big.Int).SetString(ops[1].Amount.Value, 10)
+	if !ok || amount.Sign() < 0 {
+		return newError(ErrConstructionCheck, "amount value is invalid")
+	}
+
+	// check currency
+	currency := ops[1].Amount.Currency
+	contract, err := cast.ToStringE(currency.Metadata[ic.ContractKey])
+	if err != nil {
+		return newError(ErrConstructionCheck, "invalid token contract")
+	}
+	token, ok := cfg.TokenByContract(contract)
+	if !ok || currency.Symbol != token.Symbol || currency.Decimals != token.Decimals {
+		return newError(ErrConstructionCheck, "invalid currency")
+	}
+
+	// check address
+	_, err = address.FromString(ops[0].Account.Address)
+	if err != nil {
+		return newError(ErrConstructionCheck, "invalid sender address")
+	}
+	_, err = address.FromString(ops[1].Account.Address)
+	if err != nil {
+		return newError(ErrConstructionCheck, "invalid recipient address")
+	}
+	return nil
+}
+
+// ioXrc20TransferToOps returns the operations of the execution if it is a
+// transfer of a configured token, nil otherwise.
+func ioXrc20TransferToOps(sender string, execution *iotextypes.Execution, cfg *config.Config) []*types.Operation {
+	token, ok := cfg.TokenByContract(execution.GetContract())
+	if !ok {
+		return nil
+	}
+	recipient, amount, ok := ic.ParseXrc20TransferData(execution.GetData())
+	if !ok {
+		return nil
+	}
+	currency := ic.TokenCurrency(token)
+	return []*types.Operation{
+		&types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 0,
+			},
+			Type: ic.Xrc20TransferType,
+			Account: &types.AccountIdentifier{
+				Address: sender,
+			},
+			Amount: &types.Amount{
+				Value:    "-" + amount.String(),
+				Currency: currency,
+			},
+		},
+		&types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 1,
+			},
+			RelatedOperations: []*types.OperationIdentifier{&types.OperationIdentifier{
+				Index: 0,
+			}},
+			Type: ic.Xrc20TransferType,
+			Account: &types.AccountIdentifier{
+				Address: recipient,
+			},
+			Amount: &types.Amount{
+				Value:    amount.String(),
+				Currency: currency,
+			},
+		},
+	}
+}
+
+func opsToIoXrc20Transfer(ops []*types.Operation) *iotextypes.Execution {
+	// the operations have been checked, so the execution is always valid
+	execution, _ := xrc20TransferExecution(
+		cast.ToString(ops[1].Amount.Currency.Metadata[ic.ContractKey]),
+		ops[1].Account.Address,
+		ops[1].Amount.Value,
+	)
+	return execution
+}
+
+func xrc20TransferExecution(contract, recipient, amountStr string) (*iotextypes.Execution, error) {
+	amount, ok := new(big.Int).SetString(amountStr, 10)
+	if !ok {
+		return nil, errors.Errorf("invalid amount %s", amountStr)
+	}
+	data, err := ic.Xrc20TransferData(recipient, amount)
+	if err != nil {
+		return nil, err
+	}
+	return &iotextypes.Execution{
+		Amount:   "0",
+		Contract: contract,
+		Data:     data,
+	}, nil
+}
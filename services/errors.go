@@ -6,7 +6,18 @@
 
 package services
 
-import "github.com/coinbase/rosetta-sdk-go/types"
+import (
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// keys of the failure details in Error.Details
+	causeKey    = "cause"
+	fieldKey    = "field"
+	nodeCodeKey = "nodeCode"
+)
 
 var (
 	ErrUnableToGetChainID = &types.Error{
@@ -42,24 +53,18 @@ var (
 	ErrUnableToGetLatestBlk = &types.Error{
 		Code:      6,
 		Message:   "unable to get latest block",
-		Retriable: true,
+		Retriable: false,
 	}
 
 	ErrUnableToGetGenesisBlk = &types.Error{
 		Code:      7,
 		Message:   "unable to get genesis block",
-		Retriable: true,
+		Retriable: false,
 	}
 
 	ErrUnableToGetAccount = &types.Error{
 		Code:      8,
 		Message:   "unable to get account",
-		Retriable: true,
-	}
-
-	ErrMustQueryByIndex = &types.Error{
-		Code:      9,
-		Message:   "blocks must be queried by index and not hash",
 		Retriable: false,
 	}
 
@@ -71,14 +76,14 @@ var (
 
 	ErrMustSpecifySubAccount = &types.Error{
 		Code:      11,
-		Message:   "a valid subaccount must be specified ('general' or 'escrow')",
+		Message:   "a valid subaccount must be specified ('rewards' or 'bucket:<index>')",
 		Retriable: false,
 	}
 
 	ErrUnableToGetBlk = &types.Error{
 		Code:      12,
 		Message:   "unable to get block",
-		Retriable: true,
+		Retriable: false,
 	}
 
 	ErrNotImplemented = &types.Error{
@@ -90,7 +95,7 @@ var (
 	ErrUnableToGetTxns = &types.Error{
 		Code:      14,
 		Message:   "unable to get transactions",
-		Retriable: true,
+		Retriable: false,
 	}
 
 	ErrUnableToSubmitTx = &types.Error{
@@ -102,7 +107,7 @@ var (
 	ErrUnableToGetNextNonce = &types.Error{
 		Code:      16,
 		Message:   "unable to get next nonce",
-		Retriable: true,
+		Retriable: false,
 	}
 
 	ErrMalformedValue = &types.Error{
@@ -114,12 +119,12 @@ var (
 	ErrUnableToGetNodeStatus = &types.Error{
 		Code:      18,
 		Message:   "unable to get node status",
-		Retriable: true,
+		Retriable: false,
 	}
 
 	ErrInvalidInputParam = &types.Error{
 		Code:      19,
-		Message:   "Invalid input param: ",
+		Message:   "invalid input param",
 		Retriable: false,
 	}
 
@@ -149,13 +154,13 @@ var (
 
 	ErrConstructionCheck = &types.Error{
 		Code:      24,
-		Message:   "operation construction check error: ",
-		Retriable: true,
+		Message:   "operation construction check error",
+		Retriable: false,
 	}
 
 	ErrServiceInternal = &types.Error{
 		Code:      25,
-		Message:   "Internal error: ",
+		Message:   "internal error",
 		Retriable: false,
 	}
 
@@ -167,34 +172,90 @@ var (
 
 	ErrUnableToEstimateGas = &types.Error{
 		Code:      27,
-		Message:   "unable to estimate gas: ",
-		Retriable: true,
+		Message:   "unable to estimate gas",
+		Retriable: false,
 	}
 
 	ErrUnableToGetSuggestGas = &types.Error{
 		Code:      28,
-		Message:   "unable to get suggest gas: ",
-		Retriable: true,
+		Message:   "unable to get suggest gas",
+		Retriable: false,
 	}
 
 	ErrUnableToGetBlkTx = &types.Error{
 		Code:      29,
 		Message:   "unable to get block transaction",
-		Retriable: true,
+		Retriable: false,
 	}
 
 	ErrUnableToGetMemPool = &types.Error{
 		Code:      30,
 		Message:   "unable to get mempool",
-		Retriable: true,
+		Retriable: false,
 	}
 
 	ErrUnableToGetMemPoolTx = &types.Error{
 		Code:      31,
 		Message:   "unable to get mempool transaction",
+		Retriable: false,
+	}
+
+	ErrBlockIdentifierMismatch = &types.Error{
+		Code:      32,
+		Message:   "block hash and index refer to different blocks",
+		Retriable: false,
+	}
+
+	ErrHistoricalBalanceUnavailable = &types.Error{
+		Code:      33,
+		Message:   "historical balance is not available on the node",
+		Retriable: false,
+	}
+
+	// ErrNodeUnavailable is returned when the node failed transiently, the
+	// request may succeed if it is retried.
+	ErrNodeUnavailable = &types.Error{
+		Code:      34,
+		Message:   "node is temporarily unavailable",
 		Retriable: true,
 	}
 
+	// ErrUnavailableOffline is returned by the endpoints that need a node when
+	// the gateway runs in offline mode.
+	ErrUnavailableOffline = &types.Error{
+		Code:      35,
+		Message:   "unavailable in offline mode",
+		Retriable: false,
+	}
+
+	// ErrSearchDisabled is returned by /search/transactions if the network is
+	// not indexed.
+	ErrSearchDisabled = &types.Error{
+		Code:      36,
+		Message:   "transaction search is not enabled",
+		Retriable: false,
+	}
+
+	ErrUnableToSearchTxs = &types.Error{
+		Code:      37,
+		Message:   "unable to search transactions",
+		Retriable: false,
+	}
+
+	// ErrEventsDisabled is returned by /events/blocks if the blocks of the
+	// network are not tracked.
+	ErrEventsDisabled = &types.Error{
+		Code:      38,
+		Message:   "block events are not enabled",
+		Retriable: false,
+	}
+
+	ErrUnableToGetEvents = &types.Error{
+		Code:      39,
+		Message:   "unable to get block events",
+		Retriable: false,
+	}
+
 	ErrorList = []*types.Error{
 		ErrUnableToGetChainID,
 		ErrInvalidBlockchain,
@@ -204,7 +265,6 @@ var (
 		ErrUnableToGetLatestBlk,
 		ErrUnableToGetGenesisBlk,
 		ErrUnableToGetAccount,
-		ErrMustQueryByIndex,
 		ErrInvalidAccountAddress,
 		ErrMustSpecifySubAccount,
 		ErrUnableToGetBlk,
@@ -227,5 +287,42 @@ var (
 		ErrUnableToGetBlkTx,
 		ErrUnableToGetMemPool,
 		ErrUnableToGetMemPoolTx,
+		ErrBlockIdentifierMismatch,
+		ErrHistoricalBalanceUnavailable,
+		ErrNodeUnavailable,
+		ErrUnavailableOffline,
+		ErrSearchDisabled,
+		ErrUnableToSearchTxs,
+		ErrEventsDisabled,
+		ErrUnableToGetEvents,
 	}
 )
+
+// newError returns a copy of the canonical error with the cause of the failure
+// in its details, the message of the canonical error is kept as is.
+func newError(terr *types.Error, cause string) *types.Error {
+	return &types.Error{
+		Code:      terr.Code,
+		Message:   terr.Message,
+		Retriable: terr.Retriable,
+		Details:   map[string]interface{}{causeKey: cause},
+	}
+}
+
+// fieldError returns a copy of the canonical error for an invalid field of the
+// request.
+func fieldError(terr *types.Error, field, cause string) *types.Error {
+	ret := newError(terr, cause)
+	ret.Details[fieldKey] = field
+	return ret
+}
+
+// wrapError returns a copy of the canonical error caused by err, the gRPC code
+// is kept as well if err comes from the node.
+func wrapError(terr *types.Error, err error) *types.Error {
+	ret := newError(terr, err.Error())
+	if s, ok := status.FromError(errors.Cause(err)); ok {
+		ret.Details[nodeCodeKey] = s.Code().String()
+	}
+	return ret
+}
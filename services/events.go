@@ -0,0 +1,52 @@
+// Copyright (c) 2020 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package services
+
+import (
+	"context"
+
+	"github.com/coinbase/rosetta-sdk-go/server"
+	"github.com/coinbase/rosetta-sdk-go/types"
+
+	"github.com/iotexproject/iotex-core-rosetta-gateway/indexer"
+	ic "github.com/iotexproject/iotex-core-rosetta-gateway/iotex-client"
+)
+
+type eventsAPIService struct {
+	clients networkClients
+	// trackers are keyed by network, they record the block events
+	trackers map[string]*indexer.EventTracker
+}
+
+// NewEventsAPIService creates a new instance of an EventsAPIService.
+func NewEventsAPIService(trackers map[string]*indexer.EventTracker, clients ...ic.IoTexClient) server.EventsAPIServicer {
+	return &eventsAPIService{
+		clients:  clients,
+		trackers: trackers,
+	}
+}
+
+// EventsBlocks implements the /events/blocks endpoint.
+func (s *eventsAPIService) EventsBlocks(
+	ctx context.Context,
+	request *types.EventsBlocksRequest,
+) (*types.EventsBlocksResponse, *types.Error) {
+	client, terr := s.clients.onlineClient(ctx, request.NetworkIdentifier)
+	if terr != nil {
+		return nil, terr
+	}
+	tracker, ok := s.trackers[client.GetConfig().NetworkIdentifier.Network]
+	if !ok {
+		return nil, ErrEventsDisabled
+	}
+
+	resp, err := tracker.Events(request.Offset, request.Limit)
+	if err != nil {
+		return nil, wrapError(ErrUnableToGetEvents, err)
+	}
+	return resp, nil
+}
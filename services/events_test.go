@@ -0,0 +1,48 @@
+// Copyright (c) 2020 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core-rosetta-gateway/indexer"
+	"github.com/iotexproject/iotex-core-rosetta-gateway/iotex-client/mock_client"
+)
+
+func TestEventsAPIService_EventsBlocks(t *testing.T) {
+	var (
+		cfg               = testConfig()
+		networkIdentifier = &types.NetworkIdentifier{
+			Blockchain: "IoTeX",
+			Network:    "testnet",
+		}
+		require = require.New(t)
+		ctrl    = gomock.NewController(t)
+		cli     = mock_client.NewMockIoTexClient(ctrl)
+	)
+	cli.EXPECT().GetConfig().Return(cfg).AnyTimes()
+
+	_, typErr := NewEventsAPIService(nil, cli).EventsBlocks(context.Background(), &types.EventsBlocksRequest{
+		NetworkIdentifier: networkIdentifier,
+	})
+	require.Equal(ErrEventsDisabled, typErr)
+
+	// the events do not need the transaction index
+	tracker := indexer.NewEventTracker(cli)
+	resp, typErr := NewEventsAPIService(map[string]*indexer.EventTracker{"testnet": tracker}, cli).EventsBlocks(context.Background(), &types.EventsBlocksRequest{
+		NetworkIdentifier: networkIdentifier,
+		Offset:            types.Int64(0),
+	})
+	require.Nil(typErr)
+	require.Zero(resp.MaxSequence)
+	require.Empty(resp.Events)
+}
@@ -9,13 +9,13 @@ import (
 
 
 type memPoolAPIService struct {
-	client ic.IoTexClient
+	clients networkClients
 }
 
 // NewMemPoolAPIService creates a new instance of an MemPoolAPIService.
-func NewMemPoolAPIService(client ic.IoTexClient) server.MempoolAPIServicer {
+func NewMemPoolAPIService(clients ...ic.IoTexClient) server.MempoolAPIServicer {
 	return &memPoolAPIService{
-		client: client,
+		clients: clients,
 	}
 }
 
@@ -24,14 +24,14 @@ func (s *memPoolAPIService) Mempool (
 	ctx context.Context,
 	request *types.NetworkRequest,
 ) (*types.MempoolResponse, *types.Error) {
-	terr := ValidateNetworkIdentifier(ctx, s.client, request.NetworkIdentifier)
+	client, terr := s.clients.onlineClient(ctx, request.NetworkIdentifier)
 	if terr != nil {
 		return nil, terr
 	}
 
-	memPool, err := s.client.GetMemPool(ctx, []string{})
+	memPool, err := client.GetMemPool(ctx, []string{})
 	if err != nil {
-		return nil, ErrUnableToGetMemPool
+		return nil, nodeError(ErrUnableToGetMemPool, err)
 	}
 
 	return &types.MempoolResponse{
@@ -44,15 +44,15 @@ func (s *memPoolAPIService) MempoolTransaction(
 	ctx context.Context,
 	request *types.MempoolTransactionRequest,
 	) (*types.MempoolTransactionResponse, *types.Error) {
-	terr := ValidateNetworkIdentifier(ctx, s.client, request.NetworkIdentifier)
+	client, terr := s.clients.onlineClient(ctx, request.NetworkIdentifier)
 	if terr != nil {
 		return nil, terr
 	}
 
 
-	trans, err := s.client.GetMemPoolTransaction(ctx, request.TransactionIdentifier.Hash)
+	trans, err := client.GetMemPoolTransaction(ctx, request.TransactionIdentifier.Hash)
 	if err != nil {
-		return nil, ErrUnableToGetMemPoolTx
+		return nil, nodeError(ErrUnableToGetMemPoolTx, err)
 	}
 
 	return &types.MempoolTransactionResponse{
@@ -8,6 +8,7 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"github.com/coinbase/rosetta-sdk-go/server"
 	"github.com/coinbase/rosetta-sdk-go/types"
@@ -17,16 +18,19 @@ import (
 
 const (
 	latestVersion = "v1.1.0"
+
+	SyncStageSynced  = "synced"
+	SyncStageSyncing = "syncing"
 )
 
 type networkAPIService struct {
-	client ic.IoTexClient
+	clients networkClients
 }
 
 // NewNetworkAPIService creates a new instance of a NetworkAPIService.
-func NewNetworkAPIService(client ic.IoTexClient) server.NetworkAPIServicer {
+func NewNetworkAPIService(clients ...ic.IoTexClient) server.NetworkAPIServicer {
 	return &networkAPIService{
-		client: client,
+		clients: clients,
 	}
 }
 
@@ -35,14 +39,14 @@ func (s *networkAPIService) NetworkList(
 	ctx context.Context,
 	request *types.MetadataRequest,
 ) (*types.NetworkListResponse, *types.Error) {
-	return &types.NetworkListResponse{
-		NetworkIdentifiers: []*types.NetworkIdentifier{
-			{
-				Blockchain: s.client.GetConfig().NetworkIdentifier.Blockchain,
-				Network:    s.client.GetConfig().NetworkIdentifier.Network,
-			},
-		},
-	}, nil
+	resp := &types.NetworkListResponse{}
+	for _, client := range s.clients {
+		resp.NetworkIdentifiers = append(resp.NetworkIdentifiers, &types.NetworkIdentifier{
+			Blockchain: client.GetConfig().NetworkIdentifier.Blockchain,
+			Network:    client.GetConfig().NetworkIdentifier.Network,
+		})
+	}
+	return resp, nil
 }
 
 // NetworkStatus implements the /network/status endpoint.
@@ -50,50 +54,85 @@ func (s *networkAPIService) NetworkStatus(
 	ctx context.Context,
 	request *types.NetworkRequest,
 ) (*types.NetworkStatusResponse, *types.Error) {
-	terr := ValidateNetworkIdentifier(ctx, s.client, request.NetworkIdentifier)
+	client, terr := s.clients.onlineClient(ctx, request.NetworkIdentifier)
 	if terr != nil {
 		return nil, terr
 	}
 
-	blk, err := s.client.GetLatestBlock(ctx)
+	blk, err := client.GetLatestBlock(ctx)
+	if err != nil {
+		return nil, nodeError(ErrUnableToGetNodeStatus, err)
+	}
+	genesisblk, err := client.GetBlock(ctx, 1)
 	if err != nil {
-		return nil, ErrUnableToGetNodeStatus
+		return nil, nodeError(ErrUnableToGetNodeStatus, err)
 	}
-	genesisblk, err := s.client.GetBlock(ctx, 1)
+	target, err := client.GetTargetHeight()
 	if err != nil {
-		return nil, ErrUnableToGetNodeStatus
+		return nil, nodeError(ErrUnableToGetNodeStatus, err)
 	}
+	// the node API doesn't expose the p2p peers of the node, the nodes the
+	// gateway is connected to are not its peers
 	resp := &types.NetworkStatusResponse{
 		CurrentBlockIdentifier: blk.BlockIdentifier,
 		CurrentBlockTimestamp:  blk.Timestamp, // ms
 		GenesisBlockIdentifier: genesisblk.BlockIdentifier,
-		Peers:                  nil,
+		SyncStatus:             syncStatus(blk, target, client.GetConfig().Server.BlockAgeLimit()),
+		Peers:                  []*types.Peer{},
 	}
 
 	return resp, nil
 }
 
+// syncStatus tells if the node is synced from the age of its latest block and
+// the highest tip of the upstream nodes, which the node is syncing to if it
+// lags behind. The node API doesn't report the height the node is syncing to.
+func syncStatus(blk *types.Block, target int64, maxBlockAge time.Duration) *types.SyncStatus {
+	var (
+		age     = time.Since(time.Unix(0, blk.Timestamp*int64(time.Millisecond)))
+		current = blk.BlockIdentifier.Index
+		synced  = age <= maxBlockAge && current >= target
+		stage   = SyncStageSyncing
+	)
+	if synced {
+		stage = SyncStageSynced
+	}
+	ret := &types.SyncStatus{
+		CurrentIndex: types.Int64(current),
+		Stage:        types.String(stage),
+		Synced:       types.Bool(synced),
+	}
+	if target > current {
+		ret.TargetIndex = types.Int64(target)
+	}
+	return ret
+}
+
 // NetworkOptions implements the /network/options endpoint.
 func (s *networkAPIService) NetworkOptions(
 	ctx context.Context,
 	request *types.NetworkRequest,
 ) (*types.NetworkOptionsResponse, *types.Error) {
-	terr := ValidateNetworkIdentifier(ctx, s.client, request.NetworkIdentifier)
+	client, terr := s.clients.client(ctx, request.NetworkIdentifier)
 	if terr != nil {
 		return nil, terr
 	}
 
-	version, err := s.client.GetVersion(ctx)
-	if err != nil {
-		return nil, ErrUnableToGetNodeStatus
+	var packageVersion string
+	// the options are needed offline too, the node version is unknown then
+	if !client.GetConfig().Server.Offline() {
+		version, err := client.GetVersion(ctx)
+		if err != nil {
+			return nil, nodeError(ErrUnableToGetNodeStatus, err)
+		}
+		packageVersion = version.GetServerMeta().GetPackageVersion()
 	}
-	packageVersion := version.GetServerMeta().GetPackageVersion()
 	if packageVersion == "" {
 		packageVersion = latestVersion
 	}
 	return &types.NetworkOptionsResponse{
 		Version: &types.Version{
-			RosettaVersion: s.client.GetConfig().Server.RosettaVersion,
+			RosettaVersion: client.GetConfig().Server.RosettaVersion,
 			NodeVersion:    packageVersion,
 		},
 		Allow: &types.Allow{
@@ -109,6 +148,11 @@ func (s *networkAPIService) NetworkOptions(
 			},
 			OperationTypes: SupportedOperationTypes(),
 			Errors:         ErrorList,
+			// the node only serves the balances at its tip, and there is
+			// neither /call nor coins
+			HistoricalBalanceLookup: false,
+			CallMethods:             []string{},
+			MempoolCoins:            false,
 		},
 	}, nil
 }
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core-rosetta-gateway/config"
+	"github.com/iotexproject/iotex-core-rosetta-gateway/iotex-client/mock_client"
+)
+
+func TestNetworkAPIService_NetworkStatus(t *testing.T) {
+	var (
+		cfg               = testConfig()
+		networkIdentifier = &types.NetworkIdentifier{
+			Blockchain: "IoTeX",
+			Network:    "testnet",
+		}
+		genesis = &types.Block{
+			BlockIdentifier: &types.BlockIdentifier{Index: 1, Hash: "genesis hash"},
+		}
+		tip = &types.Block{
+			BlockIdentifier: &types.BlockIdentifier{Index: 100, Hash: "tip hash"},
+			Timestamp:       time.Now().UnixNano() / int64(time.Millisecond),
+		}
+		require = require.New(t)
+		ctrl    = gomock.NewController(t)
+		cli     = mock_client.NewMockIoTexClient(ctrl)
+		clt     = NewNetworkAPIService(cli)
+	)
+	cli.EXPECT().GetConfig().Return(cfg).AnyTimes()
+	cli.EXPECT().GetLatestBlock(gomock.Any()).Return(tip, nil).AnyTimes()
+	cli.EXPECT().GetBlock(gomock.Any(), int64(1)).Return(genesis, nil).AnyTimes()
+	cli.EXPECT().GetTargetHeight().Return(int64(100), nil).Times(1)
+
+	resp, typErr := clt.NetworkStatus(context.Background(), &types.NetworkRequest{
+		NetworkIdentifier: networkIdentifier,
+	})
+	require.Nil(typErr)
+	require.Equal(tip.BlockIdentifier, resp.CurrentBlockIdentifier)
+	require.Equal(genesis.BlockIdentifier, resp.GenesisBlockIdentifier)
+	// the nodes the gateway is connected to are not reported as peers
+	require.Empty(resp.Peers)
+	require.Equal(int64(100), *resp.SyncStatus.CurrentIndex)
+	require.Nil(resp.SyncStatus.TargetIndex)
+	require.Equal(SyncStageSynced, *resp.SyncStatus.Stage)
+	require.True(*resp.SyncStatus.Synced)
+
+	// a node behind the other upstream nodes is syncing to their tip
+	cli.EXPECT().GetTargetHeight().Return(int64(110), nil).Times(1)
+	resp, typErr = clt.NetworkStatus(context.Background(), &types.NetworkRequest{
+		NetworkIdentifier: networkIdentifier,
+	})
+	require.Nil(typErr)
+	require.Equal(int64(100), *resp.SyncStatus.CurrentIndex)
+	require.Equal(int64(110), *resp.SyncStatus.TargetIndex)
+	require.Equal(SyncStageSyncing, *resp.SyncStatus.Stage)
+	require.False(*resp.SyncStatus.Synced)
+
+	// an old tip is not synced
+	cli.EXPECT().GetTargetHeight().Return(int64(100), nil).Times(1)
+	tip.Timestamp = time.Now().Add(-time.Hour).UnixNano() / int64(time.Millisecond)
+	resp, typErr = clt.NetworkStatus(context.Background(), &types.NetworkRequest{
+		NetworkIdentifier: networkIdentifier,
+	})
+	require.Nil(typErr)
+	require.Equal(SyncStageSyncing, *resp.SyncStatus.Stage)
+	require.False(*resp.SyncStatus.Synced)
+}
+
+func TestNetworkAPIService_Networks(t *testing.T) {
+	var (
+		testnetCfg = testConfig()
+		mainnetCfg = testConfig()
+		require    = require.New(t)
+		ctrl       = gomock.NewController(t)
+		testnet    = mock_client.NewMockIoTexClient(ctrl)
+		mainnet    = mock_client.NewMockIoTexClient(ctrl)
+		clt        = NewNetworkAPIService(testnet, mainnet)
+		tip        = &types.Block{
+			BlockIdentifier: &types.BlockIdentifier{Index: 100, Hash: "mainnet tip hash"},
+			Timestamp:       time.Now().UnixNano() / int64(time.Millisecond),
+		}
+	)
+	mainnetCfg.NetworkIdentifier.Network = "mainnet"
+	testnet.EXPECT().GetConfig().Return(testnetCfg).AnyTimes()
+	mainnet.EXPECT().GetConfig().Return(mainnetCfg).AnyTimes()
+
+	list, typErr := clt.NetworkList(context.Background(), &types.MetadataRequest{})
+	require.Nil(typErr)
+	require.Equal([]*types.NetworkIdentifier{
+		{Blockchain: "IoTeX", Network: "testnet"},
+		{Blockchain: "IoTeX", Network: "mainnet"},
+	}, list.NetworkIdentifiers)
+
+	// the request goes to the client of its network only
+	mainnet.EXPECT().GetLatestBlock(gomock.Any()).Return(tip, nil).Times(1)
+	mainnet.EXPECT().GetBlock(gomock.Any(), int64(1)).Return(tip, nil).Times(1)
+	mainnet.EXPECT().GetTargetHeight().Return(int64(100), nil).Times(1)
+	resp, typErr := clt.NetworkStatus(context.Background(), &types.NetworkRequest{
+		NetworkIdentifier: &types.NetworkIdentifier{Blockchain: "IoTeX", Network: "mainnet"},
+	})
+	require.Nil(typErr)
+	require.Equal(tip.BlockIdentifier, resp.CurrentBlockIdentifier)
+
+	_, typErr = clt.NetworkStatus(context.Background(), &types.NetworkRequest{
+		NetworkIdentifier: &types.NetworkIdentifier{Blockchain: "IoTeX", Network: "devnet"},
+	})
+	require.Equal(ErrInvalidNetwork.Code, typErr.Code)
+}
+
+func TestNetworkAPIService_OfflineMode(t *testing.T) {
+	var (
+		cfg               = testConfig()
+		networkIdentifier = &types.NetworkIdentifier{
+			Blockchain: "IoTeX",
+			Network:    "testnet",
+		}
+		require = require.New(t)
+		ctrl    = gomock.NewController(t)
+		cli     = mock_client.NewMockIoTexClient(ctrl)
+		clt     = NewNetworkAPIService(cli)
+	)
+	cfg.Server.Mode = config.OfflineMode
+	cli.EXPECT().GetConfig().Return(cfg).AnyTimes()
+
+	// the node is never called
+	_, typErr := clt.NetworkStatus(context.Background(), &types.NetworkRequest{
+		NetworkIdentifier: networkIdentifier,
+	})
+	require.Equal(ErrUnavailableOffline, typErr)
+	resp, typErr := clt.NetworkOptions(context.Background(), &types.NetworkRequest{
+		NetworkIdentifier: networkIdentifier,
+	})
+	require.Nil(typErr)
+	require.Equal(latestVersion, resp.Version.NodeVersion)
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2020 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package services
+
+import (
+	"context"
+
+	"github.com/coinbase/rosetta-sdk-go/server"
+	"github.com/coinbase/rosetta-sdk-go/types"
+
+	"github.com/iotexproject/iotex-core-rosetta-gateway/indexer"
+	ic "github.com/iotexproject/iotex-core-rosetta-gateway/iotex-client"
+)
+
+type searchAPIService struct {
+	clients networkClients
+	// indexers are keyed by network, the networks without one are not
+	// searchable
+	indexers map[string]*indexer.Indexer
+}
+
+// NewSearchAPIService creates a new instance of a SearchAPIService.
+func NewSearchAPIService(indexers map[string]*indexer.Indexer, clients ...ic.IoTexClient) server.SearchAPIServicer {
+	return &searchAPIService{
+		clients:  clients,
+		indexers: indexers,
+	}
+}
+
+// SearchTransactions implements the /search/transactions endpoint. The status
+// condition is rejected by the asserter of the SDK as it does not know the
+// operation statuses of the server, the success condition covers them.
+func (s *searchAPIService) SearchTransactions(
+	ctx context.Context,
+	request *types.SearchTransactionsRequest,
+) (*types.SearchTransactionsResponse, *types.Error) {
+	client, terr := s.clients.onlineClient(ctx, request.NetworkIdentifier)
+	if terr != nil {
+		return nil, terr
+	}
+	idx, ok := s.indexers[client.GetConfig().NetworkIdentifier.Network]
+	if !ok {
+		return nil, ErrSearchDisabled
+	}
+	if request.CoinIdentifier != nil {
+		return nil, fieldError(ErrInvalidInputParam, "coin_identifier", "the account based chain has no coins")
+	}
+
+	resp, err := idx.Search(request)
+	if err != nil {
+		return nil, wrapError(ErrUnableToSearchTxs, err)
+	}
+	return resp, nil
+}
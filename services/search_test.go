@@ -0,0 +1,59 @@
+// Copyright (c) 2020 IoTeX Foundation
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core-rosetta-gateway/indexer"
+	"github.com/iotexproject/iotex-core-rosetta-gateway/iotex-client/mock_client"
+)
+
+func TestSearchAPIService_SearchTransactions(t *testing.T) {
+	var (
+		cfg               = testConfig()
+		networkIdentifier = &types.NetworkIdentifier{
+			Blockchain: "IoTeX",
+			Network:    "testnet",
+		}
+		require = require.New(t)
+		ctrl    = gomock.NewController(t)
+		cli     = mock_client.NewMockIoTexClient(ctrl)
+	)
+	cfg.Index.DataDir = t.TempDir()
+	cli.EXPECT().GetConfig().Return(cfg).AnyTimes()
+
+	// the network is not indexed
+	_, typErr := NewSearchAPIService(nil, cli).SearchTransactions(context.Background(), &types.SearchTransactionsRequest{
+		NetworkIdentifier: networkIdentifier,
+	})
+	require.Equal(ErrSearchDisabled, typErr)
+
+	idx, err := indexer.New(cli)
+	require.NoError(err)
+	defer idx.Close()
+	clt := NewSearchAPIService(map[string]*indexer.Indexer{"testnet": idx}, cli)
+	resp, typErr := clt.SearchTransactions(context.Background(), &types.SearchTransactionsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Address:           types.String("io1"),
+	})
+	require.Nil(typErr)
+	require.Zero(resp.TotalCount)
+	require.Empty(resp.Transactions)
+
+	_, typErr = clt.SearchTransactions(context.Background(), &types.SearchTransactionsRequest{
+		NetworkIdentifier: networkIdentifier,
+		CoinIdentifier:    &types.CoinIdentifier{Identifier: "coin"},
+	})
+	require.Equal(ErrInvalidInputParam.Code, typErr.Code)
+	require.Equal("coin_identifier", typErr.Details[fieldKey])
+}